@@ -1,9 +1,40 @@
 package authexpr
 
 import (
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
+// RequestAttributes holds the attributes of a request that authorization
+// expressions can refer to.
+type RequestAttributes struct {
+	// Method is the HTTP method, or the full gRPC method name.
+	Method string
+	// PathParams holds the path parameters of a REST request.
+	PathParams map[string]string
+	// QueryParams holds the query parameters of a REST request.
+	QueryParams url.Values
+	// Header holds the header of a REST request, or the metadata of a gRPC request.
+	Header http.Header
+	// TLSPeer holds the identity of the verified client certificate of a mutual TLS request,
+	// or nil.
+	TLSPeer *TLSPeer
+}
+
+// TLSPeer holds the identity of the client certificate of a mutual TLS request, for the
+// tlsPeer* Atoms.
+type TLSPeer struct {
+	// Subject is the distinguished name of the certificate subject.
+	Subject string
+	// SANs holds the subject alternative names of the certificate: DNS names, URIs, email
+	// addresses and IP addresses.
+	SANs []string
+	// SPIFFEID is the SPIFFE ID of the certificate, if any.
+	SPIFFEID string
+}
+
 func MakeStandardJWTHasScope(claims map[string]interface{}) func(scope string) (bool, error) {
 	return func(queryScope string) (bool, error) {
 		// Ref: https://tools.ietf.org/html/rfc8693#section-4.2
@@ -34,3 +65,64 @@ func MakeStandardJWTHasScope(claims map[string]interface{}) func(scope string) (
 		return false, nil
 	}
 }
+
+// claimString returns the string form of a claim of a simple type.
+func claimString(claim interface{}) (string, bool) {
+	switch c := claim.(type) {
+	case string:
+		return c, true
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(c), true
+	default:
+		return "", false
+	}
+}
+
+// claimEquals reports whether the claim is of a simple type and its string
+// form equals the value.
+func claimEquals(claim interface{}, value string) bool {
+	s, ok := claimString(claim)
+	return ok && s == value
+}
+
+// claimContains reports whether the claim is an array with an element equal
+// to the value, or a string of space-separated words with a word equal to
+// the value.
+func claimContains(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case []interface{}:
+		for _, item := range c {
+			if claimEquals(item, value) {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range c {
+			if item == value {
+				return true
+			}
+		}
+	case string:
+		for _, word := range strings.Fields(c) {
+			if word == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// audienceHas reports whether the aud claim, which may be a single string or
+// an array of strings (RFC 7519, section 4.1.3), has the value.
+func audienceHas(aud interface{}, value string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == value
+	case []interface{}, []string:
+		return claimContains(a, value)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,195 @@
+package authexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Trace describes the evaluation of an expression: its result, the inputs of
+// an Atom and the traces of the arguments of an OpExpr.
+type Trace struct {
+	// Op is the name of the OpExpr, or empty for an Atom.
+	Op string `json:"op,omitempty"`
+	// Expr is the representation of the expression.
+	Expr   string       `json:"expr"`
+	Result bool         `json:"result"`
+	Inputs []TraceInput `json:"inputs,omitempty"`
+	Args   []*Trace     `json:"args,omitempty"`
+}
+
+// TraceInput is a value an Atom was evaluated against: a claim of the JWT or
+// an attribute of the request. Value is nil if the claim or attribute is missing.
+type TraceInput struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Explain evaluates the expression as Evaluate does, and returns the trace of
+// the evaluation of the expression and each of its sub-expressions.
+func (e *Expr) Explain(evalCtx EvaluationContext) (*Trace, error) {
+	if e.OpExpr != nil {
+		return e.OpExpr.Explain(evalCtx)
+	}
+	return e.AtomExpr.Explain(evalCtx)
+}
+
+// Cause returns the representation of the sub-expression that caused the
+// expression to evaluate to false: the first false argument of an all(...),
+// recursively, or else the outermost any(...), not(...) or Atom that evaluated
+// to false. It returns an empty string if the expression evaluated to true.
+func (t *Trace) Cause() string {
+	if t.Result {
+		return ""
+	}
+	if t.Op == "all" {
+		for _, arg := range t.Args {
+			if !arg.Result {
+				return arg.Cause()
+			}
+		}
+	}
+	return t.Expr
+}
+
+func (e *OpExpr) Explain(evalCtx EvaluationContext) (*Trace, error) {
+	trace := &Trace{Op: e.Name, Expr: e.Repr(), Args: make([]*Trace, len(e.Args))}
+	for i, arg := range e.Args {
+		argTrace, err := arg.Explain(evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		trace.Args[i] = argTrace
+	}
+	switch e.Name {
+	case "not":
+		trace.Result = !trace.Args[0].Result
+	case "any":
+		for _, arg := range trace.Args {
+			trace.Result = trace.Result || arg.Result
+		}
+	case "all":
+		trace.Result = true
+		for _, arg := range trace.Args {
+			trace.Result = trace.Result && arg.Result
+		}
+	default:
+		return nil, ValidationFailed("undefined OpExpr for name: %s", e.Name)
+	}
+	return trace, nil
+}
+
+func (e *Atom) Explain(evalCtx EvaluationContext) (*Trace, error) {
+	result, err := e.Evaluate(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	trace := &Trace{Expr: e.Repr(), Result: result}
+	if claim := e.claimName(); claim != "" {
+		var value interface{}
+		if v, ok := evalCtx.Claims[claim]; ok {
+			value = v
+		}
+		trace.Inputs = append(trace.Inputs, TraceInput{Name: fmt.Sprintf("claim %q", claim), Value: value})
+	}
+	if name, value, ok := e.tlsPeerInput(evalCtx); ok {
+		trace.Inputs = append(trace.Inputs, TraceInput{Name: name, Value: value})
+	}
+	for _, arg := range e.Args {
+		if arg.Ref == nil {
+			continue
+		}
+		var value interface{}
+		if v, ok := arg.Ref.Evaluate(evalCtx); ok {
+			value = v
+		}
+		trace.Inputs = append(trace.Inputs, TraceInput{Name: arg.Ref.Repr(), Value: value})
+	}
+	return trace, nil
+}
+
+// claimName returns the name of the claim the Atom checks, if any.
+func (e *Atom) claimName() string {
+	switch e.Name {
+	case "jwtHasScope":
+		return "scope"
+	case "jwtClaimEquals", "jwtClaimContains", "jwtClaimMatches":
+		return *e.Args[0].String
+	case "jwtIssuerIs":
+		return "iss"
+	case "jwtAudienceHas":
+		return "aud"
+	default:
+		return ""
+	}
+}
+
+// tlsPeerInput returns the attribute of the TLS peer the Atom checks, if any.
+func (e *Atom) tlsPeerInput(evalCtx EvaluationContext) (string, interface{}, bool) {
+	var name string
+	switch e.Name {
+	case "tlsPeerHasSAN":
+		name = "tlsPeer SANs"
+	case "tlsPeerSubjectIs":
+		name = "tlsPeer subject"
+	case "tlsPeerSPIFFEIDIs":
+		name = "tlsPeer SPIFFE ID"
+	default:
+		return "", nil, false
+	}
+	peer := evalCtx.Request.TLSPeer
+	if peer == nil {
+		return name, nil, true
+	}
+	switch e.Name {
+	case "tlsPeerHasSAN":
+		return name, peer.SANs, true
+	case "tlsPeerSubjectIs":
+		return name, peer.Subject, true
+	default:
+		return name, peer.SPIFFEID, true
+	}
+}
+
+// String renders the trace as an indented tree, one expression per line, e.g.
+//
+//	all => false
+//	  jwtHasScope("read") => false (claim "scope"="write")
+//	  equals($method(),"GET") => true ($method()="GET")
+func (t *Trace) String() string {
+	var b strings.Builder
+	t.write(&b, 0)
+	return b.String()
+}
+
+func (t *Trace) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if t.Op != "" {
+		b.WriteString(t.Op)
+	} else {
+		b.WriteString(t.Expr)
+	}
+	fmt.Fprintf(b, " => %t", t.Result)
+	if len(t.Inputs) > 0 {
+		inputs := make([]string, len(t.Inputs))
+		for i, input := range t.Inputs {
+			inputs[i] = input.Name + "=" + formatTraceValue(input.Value)
+		}
+		fmt.Fprintf(b, " (%s)", strings.Join(inputs, ", "))
+	}
+	b.WriteString("\n")
+	for _, arg := range t.Args {
+		arg.write(b, depth+1)
+	}
+}
+
+func formatTraceValue(value interface{}) string {
+	if value == nil {
+		return "<missing>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,101 @@
+package authexpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CompileExpression(`all(jwtHasScope("read"), any(jwtClaimEquals("sub", $pathParam("id")), not(equals($method(), "GET"))))`)
+	require.NoError(t, err)
+
+	claims := map[string]interface{}{"scope": "read write", "sub": "bob"}
+	evalCtx := EvaluationContext{
+		Claims:  claims,
+		Request: RequestAttributes{Method: "GET", PathParams: map[string]string{"id": "alice"}},
+	}
+	trace, err := expr.Explain(evalCtx)
+	require.NoError(t, err)
+
+	result, err := expr.Evaluate(evalCtx)
+	require.NoError(t, err)
+	require.Equal(t, result, trace.Result)
+	require.False(t, trace.Result)
+
+	require.Equal(t, "all", trace.Op)
+	require.Len(t, trace.Args, 2)
+	require.True(t, trace.Args[0].Result)
+	require.Equal(t, []TraceInput{{Name: `claim "scope"`, Value: "read write"}}, trace.Args[0].Inputs)
+	require.False(t, trace.Args[1].Result)
+	require.Equal(t, []TraceInput{
+		{Name: `claim "sub"`, Value: "bob"},
+		{Name: `$pathParam("id")`, Value: "alice"},
+	}, trace.Args[1].Args[0].Inputs)
+
+	require.Equal(t, `all => false
+  jwtHasScope("read") => true (claim "scope"="read write")
+  any => false
+    jwtClaimEquals("sub",$pathParam("id")) => false (claim "sub"="bob", $pathParam("id")="alice")
+    not => false
+      equals($method(),"GET") => true ($method()="GET")
+`, trace.String())
+
+	data, err := json.Marshal(trace.Args[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"expr":"jwtHasScope(\"read\")","result":true,"inputs":[{"name":"claim \"scope\"","value":"read write"}]}`, string(data))
+}
+
+func TestExplainMissingInputs(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CompileExpression(`jwtIssuerIs($header("X-Issuer"))`)
+	require.NoError(t, err)
+
+	trace, err := expr.Explain(EvaluationContext{})
+	require.NoError(t, err)
+	require.False(t, trace.Result)
+	require.Equal(t, "jwtIssuerIs($header(\"X-Issuer\")) => false (claim \"iss\"=<missing>, $header(\"X-Issuer\")=<missing>)\n", trace.String())
+}
+
+func TestExplainError(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CompileExpression(`any(jwtHasScope("a"), jwtHasScope("b"))`)
+	require.NoError(t, err)
+
+	_, err = expr.Explain(EvaluationContext{JWTHasScope: func(string) (bool, error) { return false, ValidationFailed("boom") }})
+	require.Error(t, err)
+}
+
+func TestTraceCause(t *testing.T) {
+	t.Parallel()
+
+	evalCtx := EvaluationContext{
+		Claims:  map[string]interface{}{"scope": "read", "sub": "bob"},
+		Request: RequestAttributes{Method: "GET"},
+	}
+	for _, tt := range []struct {
+		input string
+		cause string
+	}{
+		{`jwtHasScope("read")`, ``},
+		{`jwtHasScope("write")`, `jwtHasScope("write")`},
+		{`all(jwtHasScope("read"), all(jwtClaimEquals("sub", "bob"), equals($method(), "POST")))`, `equals($method(),"POST")`},
+		{`all(jwtHasScope("read"), any(jwtHasScope("write"), jwtClaimEquals("sub", "alice")))`, `any(jwtHasScope("write"),jwtClaimEquals("sub","alice"))`},
+		{`all(jwtHasScope("write"), not(jwtHasScope("read")))`, `jwtHasScope("write")`},
+		{`not(jwtHasScope("read"))`, `not(jwtHasScope("read"))`},
+	} {
+		expr, err := CompileExpression(tt.input)
+		require.NoError(t, err)
+		_, cause, err := expr.EvaluateWithCause(evalCtx)
+		require.NoError(t, err)
+		require.Equal(t, tt.cause, cause, tt.input)
+		trace, err := expr.Explain(evalCtx)
+		require.NoError(t, err)
+		require.Equal(t, tt.cause, trace.Cause(), tt.input)
+	}
+}
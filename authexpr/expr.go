@@ -2,6 +2,7 @@ package authexpr
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -39,10 +40,24 @@ type OpExpr struct {
 type Atom struct {
 	Name string     `parser:"@Ident"`
 	Args []*Literal `parser:"\"(\" (@@ (\",\" @@)* )? \",\"? \")\""`
+
+	// pattern is the compiled pattern of a jwtClaimMatches Atom, set by Validate.
+	pattern *regexp.Regexp
 }
 
+// Literals are the arguments of Atoms: either a string or a reference to
+// an attribute of the request being authorized.
 type Literal struct {
-	String *string `parser:"@String"`
+	String *string `parser:"  @String"`
+	Ref    *Ref    `parser:"| @@"`
+}
+
+// Refs look like function calls prefixed with a $, e.g. $pathParam("id"),
+// and evaluate to the value of an attribute of the request. The prefix
+// distinguishes them from Atoms.
+type Ref struct {
+	Name string  `parser:"\"$\" @Ident"`
+	Arg  *string `parser:"\"(\" @String? \")\""`
 }
 
 func (e *Expr) Validate() error {
@@ -82,6 +97,31 @@ func (e *Atom) Validate() error {
 		if len(e.Args) != 1 || e.Args[0].String == nil {
 			return ValidationFailed("jwtHasScope(...) Atom must be called with exactly one string literal argument")
 		}
+	case "jwtClaimEquals", "jwtClaimContains":
+		if len(e.Args) != 2 || e.Args[0].String == nil {
+			return ValidationFailed("%s(...) Atom must be called with a claim name string literal and a value argument", e.Name)
+		}
+	case "jwtClaimMatches":
+		if len(e.Args) != 2 || e.Args[0].String == nil || e.Args[1].String == nil {
+			return ValidationFailed("jwtClaimMatches(...) Atom must be called with a claim name and a pattern string literal argument")
+		}
+		pattern, err := compileClaimPattern(*e.Args[1].String)
+		if err != nil {
+			return err
+		}
+		e.pattern = pattern
+	case "jwtIssuerIs", "jwtAudienceHas":
+		if len(e.Args) != 1 {
+			return ValidationFailed("%s(...) Atom must be called with exactly one argument", e.Name)
+		}
+	case "tlsPeerHasSAN", "tlsPeerSubjectIs", "tlsPeerSPIFFEIDIs":
+		if len(e.Args) != 1 {
+			return ValidationFailed("%s(...) Atom must be called with exactly one argument", e.Name)
+		}
+	case "equals":
+		if len(e.Args) != 2 {
+			return ValidationFailed("equals(...) Atom must be called with exactly two arguments")
+		}
 	default:
 		return ValidationFailed("undefined Atom for name: %s", e.Name)
 	}
@@ -94,12 +134,45 @@ func (e *Atom) Validate() error {
 	return nil
 }
 
+// compileClaimPattern compiles the pattern of a jwtClaimMatches Atom, which
+// must match the whole claim.
+func compileClaimPattern(pattern string) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, ValidationFailed("jwtClaimMatches(...) Atom has an invalid pattern: %s", err)
+	}
+	return regexp.MustCompile("^(?:" + pattern + ")$"), nil
+}
+
 func (e *Literal) Validate() error {
+	if e.Ref != nil {
+		return e.Ref.Validate()
+	}
+	return nil
+}
+
+func (e *Ref) Validate() error {
+	switch e.Name {
+	case "pathParam", "queryParam", "header":
+		if e.Arg == nil {
+			return ValidationFailed("$%s(...) Ref must be called with exactly one string literal argument", e.Name)
+		}
+	case "method":
+		if e.Arg != nil {
+			return ValidationFailed("$method() Ref must be called without arguments")
+		}
+	default:
+		return ValidationFailed("undefined Ref for name: %s", e.Name)
+	}
 	return nil
 }
 
 type EvaluationContext struct {
 	JWTHasScope func(scope string) (bool, error)
+	// Claims holds the claims of the JWT, for the jwtClaim*, jwtIssuerIs
+	// and jwtAudienceHas Atoms.
+	Claims map[string]interface{}
+	// Request holds the attributes of the request being authorized, for Refs.
+	Request RequestAttributes
 }
 
 func (e *Expr) Evaluate(evalCtx EvaluationContext) (bool, error) {
@@ -143,12 +216,113 @@ func (e *OpExpr) Evaluate(evalCtx EvaluationContext) (bool, error) {
 func (e *Atom) Evaluate(evalCtx EvaluationContext) (bool, error) {
 	switch e.Name {
 	case "jwtHasScope":
+		if evalCtx.JWTHasScope == nil {
+			return MakeStandardJWTHasScope(evalCtx.Claims)(*(e.Args[0].String))
+		}
 		return evalCtx.JWTHasScope(*(e.Args[0].String))
+	case "jwtClaimEquals":
+		value, ok := e.Args[1].Evaluate(evalCtx)
+		return ok && claimEquals(evalCtx.Claims[*e.Args[0].String], value), nil
+	case "jwtClaimContains":
+		value, ok := e.Args[1].Evaluate(evalCtx)
+		return ok && claimContains(evalCtx.Claims[*e.Args[0].String], value), nil
+	case "jwtClaimMatches":
+		pattern := e.pattern
+		if pattern == nil {
+			var err error
+			if pattern, err = compileClaimPattern(*e.Args[1].String); err != nil {
+				return false, err
+			}
+		}
+		claim, ok := claimString(evalCtx.Claims[*e.Args[0].String])
+		return ok && pattern.MatchString(claim), nil
+	case "jwtIssuerIs":
+		value, ok := e.Args[0].Evaluate(evalCtx)
+		return ok && claimEquals(evalCtx.Claims["iss"], value), nil
+	case "jwtAudienceHas":
+		value, ok := e.Args[0].Evaluate(evalCtx)
+		return ok && audienceHas(evalCtx.Claims["aud"], value), nil
+	case "tlsPeerHasSAN":
+		value, ok := e.Args[0].Evaluate(evalCtx)
+		peer := evalCtx.Request.TLSPeer
+		if !ok || peer == nil {
+			return false, nil
+		}
+		for _, san := range peer.SANs {
+			if san == value {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "tlsPeerSubjectIs":
+		value, ok := e.Args[0].Evaluate(evalCtx)
+		peer := evalCtx.Request.TLSPeer
+		return ok && peer != nil && peer.Subject == value, nil
+	case "tlsPeerSPIFFEIDIs":
+		value, ok := e.Args[0].Evaluate(evalCtx)
+		peer := evalCtx.Request.TLSPeer
+		return ok && peer != nil && peer.SPIFFEID != "" && peer.SPIFFEID == value, nil
+	case "equals":
+		a, okA := e.Args[0].Evaluate(evalCtx)
+		b, okB := e.Args[1].Evaluate(evalCtx)
+		return okA && okB && a == b, nil
 	default:
 		return false, ValidationFailed("undefined Atom for name: %s", e.Name)
 	}
 }
 
+// Evaluate returns the value of the Literal, and false if it refers to an
+// attribute the request does not have.
+func (e *Literal) Evaluate(evalCtx EvaluationContext) (string, bool) {
+	if e.Ref != nil {
+		return e.Ref.Evaluate(evalCtx)
+	}
+	return *e.String, true
+}
+
+// Evaluate returns the value of the request attribute, and false if the
+// request does not have it.
+func (e *Ref) Evaluate(evalCtx EvaluationContext) (string, bool) {
+	req := evalCtx.Request
+	switch e.Name {
+	case "pathParam":
+		value, ok := req.PathParams[*e.Arg]
+		return value, ok
+	case "queryParam":
+		values, ok := req.QueryParams[*e.Arg]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case "header":
+		values := req.Header.Values(*e.Arg)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case "method":
+		return req.Method, req.Method != ""
+	default:
+		return "", false
+	}
+}
+
+// EvaluateWithCause evaluates the expression as Evaluate does. When the
+// expression evaluates to false, it also returns the representation of the
+// sub-expression that caused it, as found by Trace.Cause. Only the evaluations
+// to false are traced.
+func (e *Expr) EvaluateWithCause(evalCtx EvaluationContext) (bool, string, error) {
+	value, err := e.Evaluate(evalCtx)
+	if err != nil || value {
+		return value, "", err
+	}
+	trace, err := e.Explain(evalCtx)
+	if err != nil {
+		return false, "", err
+	}
+	return trace.Result, trace.Cause(), nil
+}
+
 func CompileExpression(expression string) (*Expr, error) {
 	root := &Expr{}
 	err := exprParser.ParseString(expression, root)
@@ -190,5 +364,15 @@ func (e *Atom) Repr() string {
 }
 
 func (e *Literal) Repr() string {
+	if e.Ref != nil {
+		return e.Ref.Repr()
+	}
 	return strconv.Quote(*e.String)
 }
+
+func (e *Ref) Repr() string {
+	if e.Arg == nil {
+		return fmt.Sprintf("$%s()", e.Name)
+	}
+	return fmt.Sprintf("$%s(%s)", e.Name, strconv.Quote(*e.Arg))
+}
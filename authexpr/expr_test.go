@@ -1,6 +1,7 @@
 package authexpr
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -194,3 +195,250 @@ func TestCompileExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestRefRepr(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CompileExpression(`all(jwtClaimEquals('sub', $pathParam("id")), equals($method( ), "GET"))`)
+	require.NoError(t, err)
+	require.Equal(t, `all(jwtClaimEquals("sub",$pathParam("id")),equals($method(),"GET"))`, expr.Repr())
+}
+
+func TestCompileExpressionValidation(t *testing.T) {
+	t.Parallel()
+
+	type scenario struct {
+		inputExprString string
+		expectedError   string
+	}
+
+	scenarios := []scenario{
+		{
+			inputExprString: `jwtClaimEquals("sub")`,
+			expectedError:   "auth expression error: expression is invalid: jwtClaimEquals(...) Atom must be called with a claim name string literal and a value argument",
+		},
+		{
+			inputExprString: `jwtClaimContains($header("x"), "admin")`,
+			expectedError:   "auth expression error: expression is invalid: jwtClaimContains(...) Atom must be called with a claim name string literal and a value argument",
+		},
+		{
+			inputExprString: `jwtClaimMatches("sub", "(")`,
+			expectedError:   "auth expression error: expression is invalid: jwtClaimMatches(...) Atom has an invalid pattern: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			inputExprString: `jwtClaimMatches("sub", $pathParam("id"))`,
+			expectedError:   "auth expression error: expression is invalid: jwtClaimMatches(...) Atom must be called with a claim name and a pattern string literal argument",
+		},
+		{
+			inputExprString: `jwtIssuerIs("a", "b")`,
+			expectedError:   "auth expression error: expression is invalid: jwtIssuerIs(...) Atom must be called with exactly one argument",
+		},
+		{
+			inputExprString: `equals("a")`,
+			expectedError:   "auth expression error: expression is invalid: equals(...) Atom must be called with exactly two arguments",
+		},
+		{
+			inputExprString: `tlsPeerHasSAN()`,
+			expectedError:   "auth expression error: expression is invalid: tlsPeerHasSAN(...) Atom must be called with exactly one argument",
+		},
+		{
+			inputExprString: `jwtAudienceHas($cookie("a"))`,
+			expectedError:   "auth expression error: expression is invalid: undefined Ref for name: cookie",
+		},
+		{
+			inputExprString: `jwtAudienceHas($header())`,
+			expectedError:   `auth expression error: expression is invalid: $header(...) Ref must be called with exactly one string literal argument`,
+		},
+		{
+			inputExprString: `equals($method("GET"), "GET")`,
+			expectedError:   "auth expression error: expression is invalid: $method() Ref must be called without arguments",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // force capture
+		t.Run(scenario.inputExprString, func(t *testing.T) {
+			t.Parallel()
+			_, err := CompileExpression(scenario.inputExprString)
+			require.EqualError(t, err, scenario.expectedError)
+		})
+	}
+}
+
+func TestEvaluateClaimsAndRequestAtoms(t *testing.T) {
+	t.Parallel()
+
+	evalCtx := EvaluationContext{
+		Claims: map[string]interface{}{
+			"iss":   "issuer",
+			"sub":   "alice",
+			"aud":   []interface{}{"api", "web"},
+			"roles": []interface{}{"user", "admin"},
+			"scope": "read write",
+			"level": float64(3),
+			"email": "alice@example.com",
+		},
+		Request: RequestAttributes{
+			Method:      "GET",
+			PathParams:  map[string]string{"id": "alice"},
+			QueryParams: map[string][]string{"tenant": {"t1"}},
+			Header:      map[string][]string{"X-Tenant": {"t1"}},
+		},
+	}
+
+	type scenario struct {
+		inputExprString string
+		expectedResult  bool
+	}
+
+	scenarios := []scenario{
+		{`jwtHasScope("write")`, true},
+		{`jwtClaimEquals("sub", $pathParam("id"))`, true},
+		{`jwtClaimEquals("sub", "bob")`, false},
+		{`jwtClaimEquals("sub", $pathParam("missing"))`, false},
+		{`jwtClaimEquals("level", "3")`, true},
+		{`jwtClaimEquals("missing", "")`, false},
+		{`jwtClaimContains("roles", "admin")`, true},
+		{`jwtClaimContains("roles", "root")`, false},
+		{`jwtClaimContains("scope", "read")`, true},
+		{`jwtClaimMatches("email", ".*@example\\.com")`, true},
+		{`jwtClaimMatches("email", "@example\\.com")`, false},
+		{`jwtClaimMatches("email", "alice@example\\.com|bob@example\\.com")`, true},
+		{`jwtClaimMatches("roles", "admin")`, false},
+		{`jwtIssuerIs("issuer")`, true},
+		{`jwtIssuerIs("other")`, false},
+		{`jwtAudienceHas("web")`, true},
+		{`jwtAudienceHas("mobile")`, false},
+		{`equals($method(), "GET")`, true},
+		{`equals($header("x-tenant"), $queryParam("tenant"))`, true},
+		{`equals($header("x-missing"), $queryParam("missing"))`, false},
+		{`all(jwtClaimEquals("sub", $pathParam("id")), not(jwtClaimContains("roles", "guest")))`, true},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // force capture
+		t.Run(scenario.inputExprString, func(t *testing.T) {
+			t.Parallel()
+			expr, err := CompileExpression(scenario.inputExprString)
+			require.NoError(t, err)
+			actualResult, err := expr.Evaluate(evalCtx)
+			require.NoError(t, err)
+			require.Equal(t, scenario.expectedResult, actualResult)
+		})
+	}
+}
+
+func TestEvaluateJWTAudienceHasStringClaim(t *testing.T) {
+	t.Parallel()
+
+	// A string aud claim is a single audience, not a list of space-separated audiences.
+	evalCtx := EvaluationContext{Claims: map[string]interface{}{"aud": "api web"}}
+	for value, expected := range map[string]bool{"api web": true, "api": false, "web": false} {
+		expr, err := CompileExpression(fmt.Sprintf(`jwtAudienceHas(%q)`, value))
+		require.NoError(t, err)
+		actualResult, err := expr.Evaluate(evalCtx)
+		require.NoError(t, err)
+		require.Equal(t, expected, actualResult, value)
+	}
+}
+
+func TestEvaluateUnvalidatedJWTClaimMatches(t *testing.T) {
+	t.Parallel()
+
+	claim, pattern := "email", "alice@.*"
+	atom := &Atom{Name: "jwtClaimMatches", Args: []*Literal{{String: &claim}, {String: &pattern}}}
+	evalCtx := EvaluationContext{Claims: map[string]interface{}{"email": "alice@example.com"}}
+	actualResult, err := atom.Evaluate(evalCtx)
+	require.NoError(t, err)
+	require.True(t, actualResult)
+
+	invalid := "("
+	atom.Args[1].String = &invalid
+	_, err = atom.Evaluate(evalCtx)
+	require.EqualError(t, err, "auth expression error: expression is invalid: jwtClaimMatches(...) Atom has an invalid pattern: error parsing regexp: missing closing ): `(`")
+}
+
+func TestEvaluateTLSPeerAtoms(t *testing.T) {
+	t.Parallel()
+
+	evalCtx := EvaluationContext{
+		Claims: map[string]interface{}{"sub": "client.example.com"},
+		Request: RequestAttributes{
+			Header: map[string][]string{"X-Client": {"client.example.com"}},
+			TLSPeer: &TLSPeer{
+				Subject:  "CN=client,O=Example",
+				SANs:     []string{"client.example.com", "spiffe://example.com/client", "10.0.0.1"},
+				SPIFFEID: "spiffe://example.com/client",
+			},
+		},
+	}
+
+	type scenario struct {
+		inputExprString string
+		expectedResult  bool
+	}
+
+	scenarios := []scenario{
+		{`tlsPeerHasSAN("client.example.com")`, true},
+		{`tlsPeerHasSAN("10.0.0.1")`, true},
+		{`tlsPeerHasSAN("other.example.com")`, false},
+		{`tlsPeerHasSAN($header("X-Client"))`, true},
+		{`tlsPeerSubjectIs("CN=client,O=Example")`, true},
+		{`tlsPeerSubjectIs("CN=other")`, false},
+		{`tlsPeerSPIFFEIDIs("spiffe://example.com/client")`, true},
+		{`tlsPeerSPIFFEIDIs("spiffe://example.com/other")`, false},
+		{`all(tlsPeerSPIFFEIDIs("spiffe://example.com/client"), jwtClaimEquals("sub", "client.example.com"))`, true},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // force capture
+		t.Run(scenario.inputExprString, func(t *testing.T) {
+			t.Parallel()
+			expr, err := CompileExpression(scenario.inputExprString)
+			require.NoError(t, err)
+			actualResult, err := expr.Evaluate(evalCtx)
+			require.NoError(t, err)
+			require.Equal(t, scenario.expectedResult, actualResult)
+
+			// Without a TLS peer, every tlsPeer* Atom is false.
+			noPeerCtx := evalCtx
+			noPeerCtx.Request.TLSPeer = nil
+			actualResult, err = expr.Evaluate(noPeerCtx)
+			require.NoError(t, err)
+			require.False(t, actualResult)
+		})
+	}
+}
+
+func TestEvaluateWithCause(t *testing.T) {
+	t.Parallel()
+
+	type scenario struct {
+		inputExprString string
+		expectedResult  bool
+		expectedCause   string
+	}
+
+	scenarios := []scenario{
+		{`all(jwtHasScope("foo"), jwtHasScope("barr"))`, true, ""},
+		{`all(jwtHasScope("foo"), all(jwtHasScope("fizz"), jwtHasScope("barr")))`, false, `jwtHasScope("fizz")`},
+		{`all(jwtHasScope("foo"), any(jwtHasScope("fizz"), jwtHasScope("buzz")))`, false, `any(jwtHasScope("fizz"),jwtHasScope("buzz"))`},
+		{`all(not(jwtHasScope("foo")))`, false, `not(jwtHasScope("foo"))`},
+		{`jwtHasScope("fizz")`, false, `jwtHasScope("fizz")`},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario // force capture
+		t.Run(scenario.inputExprString, func(t *testing.T) {
+			t.Parallel()
+			expr, err := CompileExpression(scenario.inputExprString)
+			require.NoError(t, err)
+			actualResult, actualCause, err := expr.EvaluateWithCause(EvaluationContext{
+				JWTHasScope: demoScopes([]string{"foo", "barr"}),
+			})
+			require.NoError(t, err)
+			require.Equal(t, scenario.expectedResult, actualResult)
+			require.Equal(t, scenario.expectedCause, actualCause)
+		})
+	}
+}
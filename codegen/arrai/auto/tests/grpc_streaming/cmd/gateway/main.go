@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	pb "grpc_streaming/internal/gen/pb/gateway"
+	gateway "grpc_streaming/internal/gen/pkg/servers/gateway"
+	encoder_backend "grpc_streaming/internal/gen/pkg/servers/gateway/encoder_backend"
+
+	"github.com/anz-bank/sysl-go/core"
+	"github.com/anz-bank/sysl-go/log"
+)
+
+type AppConfig struct{}
+
+// Split streams the encoding of each word of the request content.
+func Split(ctx context.Context, req *pb.EncodeRequest, stream pb.Gateway_SplitServer, client gateway.SplitClient) error {
+	words, err := client.Encoder_backendRot13Words(ctx, &encoder_backend.EncodingRequest{Content: req.Content})
+	if err != nil {
+		return err
+	}
+	for {
+		word, err := words.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.EncodeResponse{Content: word.Content}); err != nil {
+			return err
+		}
+	}
+}
+
+// Join responds with the encoding of the content of all streamed requests.
+func Join(ctx context.Context, stream pb.Gateway_JoinServer, client gateway.JoinClient) error {
+	all, err := client.Encoder_backendRot13All(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := all.Send(&encoder_backend.EncodingRequest{Content: req.Content}); err != nil {
+			return err
+		}
+	}
+	resp, err := all.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.EncodeResponse{Content: resp.Content})
+}
+
+// EncodeEach streams the encoding of the content of each streamed request.
+func EncodeEach(ctx context.Context, stream pb.Gateway_EncodeEachServer, client gateway.EncodeEachClient) error {
+	each, err := client.Encoder_backendRot13(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return each.CloseSend()
+		}
+		if err != nil {
+			return err
+		}
+		if err := each.Send(&encoder_backend.EncodingRequest{Content: req.Content}); err != nil {
+			return err
+		}
+		resp, err := each.Recv()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.EncodeResponse{Content: resp.Content}); err != nil {
+			return err
+		}
+	}
+}
+
+func newAppServer(ctx context.Context) (core.StoppableServer, error) {
+	return gateway.NewServer(ctx,
+		func(ctx context.Context, cfg AppConfig) (*gateway.GrpcServiceInterface, *core.Hooks, error) {
+			return &gateway.GrpcServiceInterface{
+				Split:      Split,
+				Join:       Join,
+				EncodeEach: EncodeEach,
+			}, nil, nil
+		},
+	)
+}
+
+func main() {
+	ctx := log.PutLogger(context.Background(), log.NewDefaultLogger())
+
+	handleError := func(err error) {
+		if err != nil {
+			log.Error(ctx, err, "something goes wrong")
+			os.Exit(1)
+		}
+	}
+
+	srv, err := newAppServer(ctx)
+	handleError(err)
+	err = srv.Start()
+	handleError(err)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"google.golang.org/grpc"
+
+	ebpb "grpc_streaming/internal/gen/pb/encoder_backend"
+	pb "grpc_streaming/internal/gen/pb/gateway"
+
+	"github.com/anz-bank/sysl-go/core"
+	"github.com/sethvargo/go-retry"
+	"github.com/stretchr/testify/require"
+)
+
+const applicationConfig = `---
+genCode:
+  upstream:
+    grpc:
+      hostName: "localhost"
+      port: 9021 # FIXME no guarantee this port is free
+  downstream:
+    contextTimeout: "30s"
+    encoder_backend:
+      serviceAddress: localhost:9022
+`
+
+func rot13(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			b.WriteRune('a' + (r-'a'+13)%26)
+		case unicode.IsUpper(r):
+			b.WriteRune('A' + (r-'A'+13)%26)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type dummyEncoderBackend struct {
+	ebpb.UnimplementedEncoderBackendServer
+}
+
+func (s dummyEncoderBackend) Rot13Words(req *ebpb.EncodingRequest, stream ebpb.EncoderBackend_Rot13WordsServer) error {
+	for _, word := range strings.Fields(req.Content) {
+		if err := stream.Send(&ebpb.EncodingResponse{Content: rot13(word)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s dummyEncoderBackend) Rot13All(stream ebpb.EncoderBackend_Rot13AllServer) error {
+	var contents []string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&ebpb.EncodingResponse{Content: rot13(strings.Join(contents, " "))})
+		}
+		if err != nil {
+			return err
+		}
+		contents = append(contents, req.Content)
+	}
+}
+
+func (s dummyEncoderBackend) Rot13(stream ebpb.EncoderBackend_Rot13Server) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&ebpb.EncodingResponse{Content: rot13(req.Content)}); err != nil {
+			return err
+		}
+	}
+}
+
+func startDummyEncoderBackendServer(addr string) (stopServer func() error) {
+	// Starts a hand-written implementation of the EncoderBackend service running on given TCP Address.
+	// Returns a function that can be used to stop the server.
+
+	server := grpc.NewServer()
+	ebpb.RegisterEncoderBackendServer(server, dummyEncoderBackend{})
+
+	c := make(chan error, 1)
+
+	go func() {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			c <- err
+			return
+		}
+		fmt.Printf("dummy encoder backend server will listen on %s...\n", addr)
+		c <- server.Serve(listener)
+	}()
+
+	stopServer = func() error {
+		// If the server stopped with some error before the caller
+		// tried to stop it, return that error instead.
+		select {
+		case err := <-c:
+			return err
+		default:
+		}
+		server.Stop()
+		return nil
+	}
+	return stopServer
+}
+
+func doGatewaySplit(ctx context.Context, client pb.GatewayClient, content string) ([]string, error) {
+	stream, err := client.Split(ctx, &pb.EncodeRequest{Content: content})
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return words, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, resp.Content)
+	}
+}
+
+func doGatewayJoin(ctx context.Context, client pb.GatewayClient, contents ...string) (string, error) {
+	stream, err := client.Join(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, content := range contents {
+		if err := stream.Send(&pb.EncodeRequest{Content: content}); err != nil {
+			return "", err
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func doGatewayEncodeEach(ctx context.Context, client pb.GatewayClient, contents ...string) ([]string, error) {
+	stream, err := client.EncodeEach(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var encoded []string
+	for _, content := range contents {
+		if err := stream.Send(&pb.EncodeRequest{Content: content}); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, resp.Content)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		return nil, fmt.Errorf("expected end of stream, got %v", err)
+	}
+	return encoded, nil
+}
+
+func TestGRPCStreamingSmokeTest(t *testing.T) {
+	// Override sysl-go app command line interface to directly pass in app config
+	ctx := core.WithConfigFile(context.Background(), []byte(applicationConfig))
+
+	// Start the dummy encoder backend service running
+	stopEncoderBackendServer := startDummyEncoderBackendServer("localhost:9022")
+	defer func() {
+		err := stopEncoderBackendServer()
+		require.NoError(t, err)
+	}()
+
+	appServer, err := newAppServer(ctx)
+	require.NoError(t, err)
+	defer func() {
+		err := appServer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	// Start application server
+	go func() {
+		err := appServer.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	conn, err := grpc.Dial("localhost:9021", grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewGatewayClient(conn)
+
+	// Wait for application to come up
+	backoff, err := retry.NewFibonacci(20 * time.Millisecond)
+	require.Nil(t, err)
+	backoff = retry.WithMaxDuration(5*time.Second, backoff)
+	err = retry.Do(ctx, backoff, func(ctx context.Context) error {
+		_, err := doGatewaySplit(ctx, client, "testing; one two, one two; is this thing on?")
+		if err != nil {
+			return retry.RetryableError(err)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Server streaming
+	words, err := doGatewaySplit(ctx, client, "hello world")
+	require.NoError(t, err)
+	require.Equal(t, []string{"uryyb", "jbeyq"}, words)
+
+	// Client streaming
+	joined, err := doGatewayJoin(ctx, client, "hello", "world")
+	require.NoError(t, err)
+	require.Equal(t, "uryyb jbeyq", joined)
+
+	// Bidirectional streaming
+	encoded, err := doGatewayEncodeEach(ctx, client, "hello", "world")
+	require.NoError(t, err)
+	require.Equal(t, []string{"uryyb", "jbeyq"}, encoded)
+}
@@ -0,0 +1 @@
+../../codegen.mk
\ No newline at end of file
@@ -0,0 +1,477 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.12.4
+// source: encoder_backend.proto
+
+package encoder_backend
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EncodingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *EncodingResponse) Reset() {
+	*x = EncodingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_backend_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodingResponse) ProtoMessage() {}
+
+func (x *EncodingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_backend_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodingResponse.ProtoReflect.Descriptor instead.
+func (*EncodingResponse) Descriptor() ([]byte, []int) {
+	return file_encoder_backend_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EncodingResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type EncodingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *EncodingRequest) Reset() {
+	*x = EncodingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_backend_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodingRequest) ProtoMessage() {}
+
+func (x *EncodingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_backend_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodingRequest.ProtoReflect.Descriptor instead.
+func (*EncodingRequest) Descriptor() ([]byte, []int) {
+	return file_encoder_backend_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EncodingRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+var File_encoder_backend_proto protoreflect.FileDescriptor
+
+var file_encoder_backend_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
+	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x22, 0x2c, 0x0a, 0x10, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x2b, 0x0a, 0x0f, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x32, 0x8a, 0x02, 0x0a, 0x0e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x42,
+	0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x53, 0x0a, 0x0a, 0x52, 0x6f, 0x74, 0x31, 0x33, 0x57,
+	0x6f, 0x72, 0x64, 0x73, 0x12, 0x20, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x5f, 0x62,
+	0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x51, 0x0a, 0x08, 0x52,
+	0x6f, 0x74, 0x31, 0x33, 0x41, 0x6c, 0x6c, 0x12, 0x20, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x50,
+	0x0a, 0x05, 0x52, 0x6f, 0x74, 0x31, 0x33, 0x12, 0x20, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2e, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_encoder_backend_proto_rawDescOnce sync.Once
+	file_encoder_backend_proto_rawDescData = file_encoder_backend_proto_rawDesc
+)
+
+func file_encoder_backend_proto_rawDescGZIP() []byte {
+	file_encoder_backend_proto_rawDescOnce.Do(func() {
+		file_encoder_backend_proto_rawDescData = protoimpl.X.CompressGZIP(file_encoder_backend_proto_rawDescData)
+	})
+	return file_encoder_backend_proto_rawDescData
+}
+
+var file_encoder_backend_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_encoder_backend_proto_goTypes = []interface{}{
+	(*EncodingResponse)(nil), // 0: encoder_backend.EncodingResponse
+	(*EncodingRequest)(nil),  // 1: encoder_backend.EncodingRequest
+}
+var file_encoder_backend_proto_depIdxs = []int32{
+	1, // 0: encoder_backend.EncoderBackend.Rot13Words:input_type -> encoder_backend.EncodingRequest
+	1, // 1: encoder_backend.EncoderBackend.Rot13All:input_type -> encoder_backend.EncodingRequest
+	1, // 2: encoder_backend.EncoderBackend.Rot13:input_type -> encoder_backend.EncodingRequest
+	0, // 3: encoder_backend.EncoderBackend.Rot13Words:output_type -> encoder_backend.EncodingResponse
+	0, // 4: encoder_backend.EncoderBackend.Rot13All:output_type -> encoder_backend.EncodingResponse
+	0, // 5: encoder_backend.EncoderBackend.Rot13:output_type -> encoder_backend.EncodingResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_encoder_backend_proto_init() }
+func file_encoder_backend_proto_init() {
+	if File_encoder_backend_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_encoder_backend_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_backend_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_encoder_backend_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_encoder_backend_proto_goTypes,
+		DependencyIndexes: file_encoder_backend_proto_depIdxs,
+		MessageInfos:      file_encoder_backend_proto_msgTypes,
+	}.Build()
+	File_encoder_backend_proto = out.File
+	file_encoder_backend_proto_rawDesc = nil
+	file_encoder_backend_proto_goTypes = nil
+	file_encoder_backend_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// EncoderBackendClient is the client API for EncoderBackend service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type EncoderBackendClient interface {
+	Rot13Words(ctx context.Context, in *EncodingRequest, opts ...grpc.CallOption) (EncoderBackend_Rot13WordsClient, error)
+	Rot13All(ctx context.Context, opts ...grpc.CallOption) (EncoderBackend_Rot13AllClient, error)
+	Rot13(ctx context.Context, opts ...grpc.CallOption) (EncoderBackend_Rot13Client, error)
+}
+
+type encoderBackendClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEncoderBackendClient(cc grpc.ClientConnInterface) EncoderBackendClient {
+	return &encoderBackendClient{cc}
+}
+
+func (c *encoderBackendClient) Rot13Words(ctx context.Context, in *EncodingRequest, opts ...grpc.CallOption) (EncoderBackend_Rot13WordsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_EncoderBackend_serviceDesc.Streams[0], "/encoder_backend.EncoderBackend/Rot13Words", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &encoderBackendRot13WordsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EncoderBackend_Rot13WordsClient interface {
+	Recv() (*EncodingResponse, error)
+	grpc.ClientStream
+}
+
+type encoderBackendRot13WordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *encoderBackendRot13WordsClient) Recv() (*EncodingResponse, error) {
+	m := new(EncodingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *encoderBackendClient) Rot13All(ctx context.Context, opts ...grpc.CallOption) (EncoderBackend_Rot13AllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_EncoderBackend_serviceDesc.Streams[1], "/encoder_backend.EncoderBackend/Rot13All", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &encoderBackendRot13AllClient{stream}
+	return x, nil
+}
+
+type EncoderBackend_Rot13AllClient interface {
+	Send(*EncodingRequest) error
+	CloseAndRecv() (*EncodingResponse, error)
+	grpc.ClientStream
+}
+
+type encoderBackendRot13AllClient struct {
+	grpc.ClientStream
+}
+
+func (x *encoderBackendRot13AllClient) Send(m *EncodingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *encoderBackendRot13AllClient) CloseAndRecv() (*EncodingResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EncodingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *encoderBackendClient) Rot13(ctx context.Context, opts ...grpc.CallOption) (EncoderBackend_Rot13Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_EncoderBackend_serviceDesc.Streams[2], "/encoder_backend.EncoderBackend/Rot13", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &encoderBackendRot13Client{stream}
+	return x, nil
+}
+
+type EncoderBackend_Rot13Client interface {
+	Send(*EncodingRequest) error
+	Recv() (*EncodingResponse, error)
+	grpc.ClientStream
+}
+
+type encoderBackendRot13Client struct {
+	grpc.ClientStream
+}
+
+func (x *encoderBackendRot13Client) Send(m *EncodingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *encoderBackendRot13Client) Recv() (*EncodingResponse, error) {
+	m := new(EncodingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EncoderBackendServer is the server API for EncoderBackend service.
+type EncoderBackendServer interface {
+	Rot13Words(*EncodingRequest, EncoderBackend_Rot13WordsServer) error
+	Rot13All(EncoderBackend_Rot13AllServer) error
+	Rot13(EncoderBackend_Rot13Server) error
+}
+
+// UnimplementedEncoderBackendServer can be embedded to have forward compatible implementations.
+type UnimplementedEncoderBackendServer struct {
+}
+
+func (*UnimplementedEncoderBackendServer) Rot13Words(*EncodingRequest, EncoderBackend_Rot13WordsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Rot13Words not implemented")
+}
+func (*UnimplementedEncoderBackendServer) Rot13All(EncoderBackend_Rot13AllServer) error {
+	return status.Errorf(codes.Unimplemented, "method Rot13All not implemented")
+}
+func (*UnimplementedEncoderBackendServer) Rot13(EncoderBackend_Rot13Server) error {
+	return status.Errorf(codes.Unimplemented, "method Rot13 not implemented")
+}
+
+func RegisterEncoderBackendServer(s *grpc.Server, srv EncoderBackendServer) {
+	s.RegisterService(&_EncoderBackend_serviceDesc, srv)
+}
+
+func _EncoderBackend_Rot13Words_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EncodingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EncoderBackendServer).Rot13Words(m, &encoderBackendRot13WordsServer{stream})
+}
+
+type EncoderBackend_Rot13WordsServer interface {
+	Send(*EncodingResponse) error
+	grpc.ServerStream
+}
+
+type encoderBackendRot13WordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *encoderBackendRot13WordsServer) Send(m *EncodingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EncoderBackend_Rot13All_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EncoderBackendServer).Rot13All(&encoderBackendRot13AllServer{stream})
+}
+
+type EncoderBackend_Rot13AllServer interface {
+	SendAndClose(*EncodingResponse) error
+	Recv() (*EncodingRequest, error)
+	grpc.ServerStream
+}
+
+type encoderBackendRot13AllServer struct {
+	grpc.ServerStream
+}
+
+func (x *encoderBackendRot13AllServer) SendAndClose(m *EncodingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *encoderBackendRot13AllServer) Recv() (*EncodingRequest, error) {
+	m := new(EncodingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _EncoderBackend_Rot13_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EncoderBackendServer).Rot13(&encoderBackendRot13Server{stream})
+}
+
+type EncoderBackend_Rot13Server interface {
+	Send(*EncodingResponse) error
+	Recv() (*EncodingRequest, error)
+	grpc.ServerStream
+}
+
+type encoderBackendRot13Server struct {
+	grpc.ServerStream
+}
+
+func (x *encoderBackendRot13Server) Send(m *EncodingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *encoderBackendRot13Server) Recv() (*EncodingRequest, error) {
+	m := new(EncodingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _EncoderBackend_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "encoder_backend.EncoderBackend",
+	HandlerType: (*EncoderBackendServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Rot13Words",
+			Handler:       _EncoderBackend_Rot13Words_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Rot13All",
+			Handler:       _EncoderBackend_Rot13All_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Rot13",
+			Handler:       _EncoderBackend_Rot13_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "encoder_backend.proto",
+}
@@ -0,0 +1,481 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.12.4
+// source: gateway.proto
+
+package gateway
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EncodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *EncodeResponse) Reset() {
+	*x = EncodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gateway_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeResponse) ProtoMessage() {}
+
+func (x *EncodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gateway_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeResponse.ProtoReflect.Descriptor instead.
+func (*EncodeResponse) Descriptor() ([]byte, []int) {
+	return file_gateway_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EncodeResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type EncodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EncoderId string `protobuf:"bytes,1,opt,name=encoder_id,json=encoderId,proto3" json:"encoder_id,omitempty"`
+	Content   string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *EncodeRequest) Reset() {
+	*x = EncodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gateway_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeRequest) ProtoMessage() {}
+
+func (x *EncodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gateway_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeRequest.ProtoReflect.Descriptor instead.
+func (*EncodeRequest) Descriptor() ([]byte, []int) {
+	return file_gateway_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EncodeRequest) GetEncoderId() string {
+	if x != nil {
+		return x.EncoderId
+	}
+	return ""
+}
+
+func (x *EncodeRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+var File_gateway_proto protoreflect.FileDescriptor
+
+var file_gateway_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x22, 0x2a, 0x0a, 0x0e, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x22, 0x48, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x64,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x32, 0xc3,
+	0x01, 0x0a, 0x07, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x3a, 0x0a, 0x05, 0x53, 0x70,
+	0x6c, 0x69, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x45, 0x6e,
+	0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x04, 0x4a, 0x6f, 0x69, 0x6e, 0x12, 0x16,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28,
+	0x01, 0x12, 0x41, 0x0a, 0x0a, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x45, 0x61, 0x63, 0x68, 0x12,
+	0x16, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gateway_proto_rawDescOnce sync.Once
+	file_gateway_proto_rawDescData = file_gateway_proto_rawDesc
+)
+
+func file_gateway_proto_rawDescGZIP() []byte {
+	file_gateway_proto_rawDescOnce.Do(func() {
+		file_gateway_proto_rawDescData = protoimpl.X.CompressGZIP(file_gateway_proto_rawDescData)
+	})
+	return file_gateway_proto_rawDescData
+}
+
+var file_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_gateway_proto_goTypes = []interface{}{
+	(*EncodeResponse)(nil), // 0: gateway.EncodeResponse
+	(*EncodeRequest)(nil),  // 1: gateway.EncodeRequest
+}
+var file_gateway_proto_depIdxs = []int32{
+	1, // 0: gateway.Gateway.Split:input_type -> gateway.EncodeRequest
+	1, // 1: gateway.Gateway.Join:input_type -> gateway.EncodeRequest
+	1, // 2: gateway.Gateway.EncodeEach:input_type -> gateway.EncodeRequest
+	0, // 3: gateway.Gateway.Split:output_type -> gateway.EncodeResponse
+	0, // 4: gateway.Gateway.Join:output_type -> gateway.EncodeResponse
+	0, // 5: gateway.Gateway.EncodeEach:output_type -> gateway.EncodeResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gateway_proto_init() }
+func file_gateway_proto_init() {
+	if File_gateway_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gateway_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gateway_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gateway_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gateway_proto_goTypes,
+		DependencyIndexes: file_gateway_proto_depIdxs,
+		MessageInfos:      file_gateway_proto_msgTypes,
+	}.Build()
+	File_gateway_proto = out.File
+	file_gateway_proto_rawDesc = nil
+	file_gateway_proto_goTypes = nil
+	file_gateway_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// GatewayClient is the client API for Gateway service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GatewayClient interface {
+	Split(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (Gateway_SplitClient, error)
+	Join(ctx context.Context, opts ...grpc.CallOption) (Gateway_JoinClient, error)
+	EncodeEach(ctx context.Context, opts ...grpc.CallOption) (Gateway_EncodeEachClient, error)
+}
+
+type gatewayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGatewayClient(cc grpc.ClientConnInterface) GatewayClient {
+	return &gatewayClient{cc}
+}
+
+func (c *gatewayClient) Split(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (Gateway_SplitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Gateway_serviceDesc.Streams[0], "/gateway.Gateway/Split", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewaySplitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gateway_SplitClient interface {
+	Recv() (*EncodeResponse, error)
+	grpc.ClientStream
+}
+
+type gatewaySplitClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewaySplitClient) Recv() (*EncodeResponse, error) {
+	m := new(EncodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gatewayClient) Join(ctx context.Context, opts ...grpc.CallOption) (Gateway_JoinClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Gateway_serviceDesc.Streams[1], "/gateway.Gateway/Join", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayJoinClient{stream}
+	return x, nil
+}
+
+type Gateway_JoinClient interface {
+	Send(*EncodeRequest) error
+	CloseAndRecv() (*EncodeResponse, error)
+	grpc.ClientStream
+}
+
+type gatewayJoinClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayJoinClient) Send(m *EncodeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gatewayJoinClient) CloseAndRecv() (*EncodeResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EncodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gatewayClient) EncodeEach(ctx context.Context, opts ...grpc.CallOption) (Gateway_EncodeEachClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Gateway_serviceDesc.Streams[2], "/gateway.Gateway/EncodeEach", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayEncodeEachClient{stream}
+	return x, nil
+}
+
+type Gateway_EncodeEachClient interface {
+	Send(*EncodeRequest) error
+	Recv() (*EncodeResponse, error)
+	grpc.ClientStream
+}
+
+type gatewayEncodeEachClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayEncodeEachClient) Send(m *EncodeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gatewayEncodeEachClient) Recv() (*EncodeResponse, error) {
+	m := new(EncodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GatewayServer is the server API for Gateway service.
+type GatewayServer interface {
+	Split(*EncodeRequest, Gateway_SplitServer) error
+	Join(Gateway_JoinServer) error
+	EncodeEach(Gateway_EncodeEachServer) error
+}
+
+// UnimplementedGatewayServer can be embedded to have forward compatible implementations.
+type UnimplementedGatewayServer struct {
+}
+
+func (*UnimplementedGatewayServer) Split(*EncodeRequest, Gateway_SplitServer) error {
+	return status.Errorf(codes.Unimplemented, "method Split not implemented")
+}
+func (*UnimplementedGatewayServer) Join(Gateway_JoinServer) error {
+	return status.Errorf(codes.Unimplemented, "method Join not implemented")
+}
+func (*UnimplementedGatewayServer) EncodeEach(Gateway_EncodeEachServer) error {
+	return status.Errorf(codes.Unimplemented, "method EncodeEach not implemented")
+}
+
+func RegisterGatewayServer(s *grpc.Server, srv GatewayServer) {
+	s.RegisterService(&_Gateway_serviceDesc, srv)
+}
+
+func _Gateway_Split_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EncodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GatewayServer).Split(m, &gatewaySplitServer{stream})
+}
+
+type Gateway_SplitServer interface {
+	Send(*EncodeResponse) error
+	grpc.ServerStream
+}
+
+type gatewaySplitServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewaySplitServer) Send(m *EncodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gateway_Join_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GatewayServer).Join(&gatewayJoinServer{stream})
+}
+
+type Gateway_JoinServer interface {
+	SendAndClose(*EncodeResponse) error
+	Recv() (*EncodeRequest, error)
+	grpc.ServerStream
+}
+
+type gatewayJoinServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayJoinServer) SendAndClose(m *EncodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gatewayJoinServer) Recv() (*EncodeRequest, error) {
+	m := new(EncodeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Gateway_EncodeEach_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GatewayServer).EncodeEach(&gatewayEncodeEachServer{stream})
+}
+
+type Gateway_EncodeEachServer interface {
+	Send(*EncodeResponse) error
+	Recv() (*EncodeRequest, error)
+	grpc.ServerStream
+}
+
+type gatewayEncodeEachServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayEncodeEachServer) Send(m *EncodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gatewayEncodeEachServer) Recv() (*EncodeRequest, error) {
+	m := new(EncodeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Gateway_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gateway.Gateway",
+	HandlerType: (*GatewayServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Split",
+			Handler:       _Gateway_Split_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Join",
+			Handler:       _Gateway_Join_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "EncodeEach",
+			Handler:       _Gateway_EncodeEach_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gateway.proto",
+}
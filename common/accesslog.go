@@ -0,0 +1,188 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anz-bank/sysl-go/common/internal"
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// combinedLogTimeFormat is the time format of the Apache combined log format.
+const combinedLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogMiddleware returns middleware that logs each completed request as configured.
+func AccessLogMiddleware(cfg *config.AccessLogConfig) func(next http.Handler) http.Handler {
+	return newAccessLogMiddleware(cfg, rand.Float64)
+}
+
+func newAccessLogMiddleware(cfg *config.AccessLogConfig, sample func() float64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			record := &accessLogRecord{}
+			ctx := context.WithValue(r.Context(), accessLogRecordKey{}, record)
+			r = r.WithContext(ctx)
+			body := &countingReadCloser{ReadCloser: r.Body}
+			if r.Body != nil {
+				r.Body = body
+			}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			// Log from a defer so that requests that panic are logged too.
+			completed := false
+			defer func() {
+				status := ww.Status()
+				switch {
+				case !completed:
+					// The panic is answered with a 500 by the Recoverer middleware.
+					status = http.StatusInternalServerError
+				case status == 0:
+					status = http.StatusOK
+				}
+				if status < 400 && cfg.SampleRate > 0 && sample() >= cfg.SampleRate {
+					return
+				}
+				entry := accessLogEntry{
+					start:    start,
+					latency:  time.Since(start),
+					status:   status,
+					bytesIn:  body.n,
+					bytesOut: ww.BytesWritten(),
+					subject:  record.subject,
+				}
+				logAccess(ctx, cfg, r, entry)
+			}()
+
+			next.ServeHTTP(ww, r)
+			completed = true
+		})
+	}
+}
+
+type accessLogEntry struct {
+	start    time.Time
+	latency  time.Duration
+	status   int
+	bytesIn  int64
+	bytesOut int
+	subject  string
+}
+
+func logAccess(ctx context.Context, cfg *config.AccessLogConfig, r *http.Request, entry accessLogEntry) {
+	var message string
+	if cfg.Format == config.AccessLogFormatCombined {
+		message = combinedLogLine(r, entry)
+	} else {
+		ctx = accessLogFields(ctx, cfg, r, entry)
+		message = "Request completed"
+	}
+
+	switch {
+	case entry.status < 400, entry.status < 500 && cfg.ClientErrorsAsInfo:
+		log.Info(ctx, message)
+	default:
+		log.Error(ctx, fmt.Errorf("invalid status: %d", entry.status), message)
+	}
+}
+
+func accessLogFields(ctx context.Context, cfg *config.AccessLogConfig, r *http.Request, entry accessLogEntry) context.Context {
+	ctx = internal.InitFieldsFromRequest(ctx, r)
+	ctx = log.WithInt(ctx, "status", entry.status)
+	ctx = log.WithDuration(ctx, "took", entry.latency)
+	ctx = log.WithStr(ctx, "latency", strconv.FormatInt(entry.latency.Nanoseconds(), 10))
+	if cfg.HasField(config.AccessLogFieldUserAgent) {
+		ctx = log.WithStr(ctx, "user_agent", r.UserAgent())
+	}
+	if cfg.HasField(config.AccessLogFieldBytesIn) {
+		ctx = log.WithInt(ctx, "bytes_in", int(entry.bytesIn))
+	}
+	if cfg.HasField(config.AccessLogFieldBytesOut) {
+		ctx = log.WithInt(ctx, "bytes_out", entry.bytesOut)
+	}
+	if cfg.HasField(config.AccessLogFieldRoute) {
+		ctx = log.WithStr(ctx, "route", routePattern(ctx))
+	}
+	if cfg.HasField(config.AccessLogFieldJWTSubject) && entry.subject != "" {
+		ctx = log.WithStr(ctx, "jwt_subject", entry.subject)
+	}
+	return ctx
+}
+
+// combinedLogLine returns the request in the Apache combined log format:
+// host ident user [time] "request line" status bytes "referer" "user agent".
+func combinedLogLine(r *http.Request, entry accessLogEntry) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	bytesOut := "-"
+	if entry.bytesOut > 0 {
+		bytesOut = strconv.Itoa(entry.bytesOut)
+	}
+	return fmt.Sprintf("%s - %s [%s] %s %d %s %s %s",
+		orDash(host),
+		orDash(entry.subject),
+		entry.start.Format(combinedLogTimeFormat),
+		strconv.Quote(fmt.Sprintf("%s %s %s", r.Method, internal.RedactURL(r.Context(), r.URL).RequestURI(), r.Proto)),
+		entry.status,
+		bytesOut,
+		strconv.Quote(orDash(r.Referer())),
+		strconv.Quote(orDash(r.UserAgent())),
+	)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func routePattern(ctx context.Context) string {
+	if rctx := chi.RouteContext(ctx); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
+type accessLogRecordKey struct{}
+
+// accessLogRecord holds the details of a request that are only known to the handlers.
+type accessLogRecord struct {
+	subject string
+}
+
+// RecordAccessLogSubject records the authenticated subject of the request for the access log.
+// It does nothing if the access log is not configured.
+func RecordAccessLogSubject(ctx context.Context, subject string) {
+	if record, ok := ctx.Value(accessLogRecordKey{}).(*accessLogRecord); ok {
+		record.subject = subject
+	}
+}
+
+// isAccessLogged returns whether the request is logged by the access log middleware.
+func isAccessLogged(ctx context.Context) bool {
+	cfg := config.GetDefaultConfig(ctx)
+	return cfg != nil && cfg.Library.Log.AccessLog != nil
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
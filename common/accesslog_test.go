@@ -0,0 +1,175 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+)
+
+func serveAccessLogged(ctx context.Context, cfg *config.AccessLogConfig, sample float64, status int, req *http.Request) {
+	router := chi.NewRouter()
+	router.Use(newAccessLogMiddleware(cfg, func() float64 { return sample }))
+	router.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		RecordAccessLogSubject(r.Context(), "alice")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("hello"))
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+}
+
+func TestAccessLogMiddlewareJSON(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	cfg := &config.AccessLogConfig{Fields: []string{
+		config.AccessLogFieldUserAgent,
+		config.AccessLogFieldBytesIn,
+		config.AccessLogFieldBytesOut,
+		config.AccessLogFieldRoute,
+		config.AccessLogFieldJWTSubject,
+	}}
+	req := httptest.NewRequest(http.MethodPost, "/items/1", strings.NewReader("body"))
+	req.Header.Set("User-Agent", "test-agent")
+
+	serveAccessLogged(ctx, cfg, 0, http.StatusCreated, req)
+
+	require.Equal(t, 1, logger.EntryCount())
+	entry := logger.LastEntry()
+	require.Equal(t, log.InfoLevel, entry.Level)
+	require.Equal(t, "Request completed", entry.Message)
+	require.Equal(t, http.StatusCreated, entry.Fields["status"])
+	require.Equal(t, http.MethodPost, entry.Fields["method"])
+	require.Equal(t, "test-agent", entry.Fields["user_agent"])
+	require.Equal(t, 4, entry.Fields["bytes_in"])
+	require.Equal(t, 5, entry.Fields["bytes_out"])
+	require.Equal(t, "/items/{id}", entry.Fields["route"])
+	require.Equal(t, "alice", entry.Fields["jwt_subject"])
+}
+
+func TestAccessLogMiddlewareCombined(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	cfg := &config.AccessLogConfig{Format: config.AccessLogFormatCombined}
+	req := httptest.NewRequest(http.MethodGet, "/items/1?full=true", nil)
+	req.Header.Set("Referer", "http://example.com/")
+
+	serveAccessLogged(ctx, cfg, 0, http.StatusOK, req)
+
+	require.Equal(t, 1, logger.EntryCount())
+	message := logger.LastEntry().Message
+	require.True(t, strings.HasPrefix(message, "192.0.2.1 - alice ["), message)
+	require.True(t, strings.HasSuffix(message, `] "GET /items/1?full=true HTTP/1.1" 200 5 "http://example.com/" "-"`), message)
+}
+
+func TestAccessLogMiddlewareRedactsQueryParams(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	defaultConfig := &config.DefaultConfig{}
+	defaultConfig.Library.Authentication = &config.AuthenticationConfig{APIKey: &credauth.APIKeyConfig{QueryParam: "api_key"}}
+	ctx = config.PutDefaultConfig(ctx, defaultConfig)
+	req := httptest.NewRequest(http.MethodGet, "/items/1?api_key=secret&full=true", nil)
+
+	serveAccessLogged(ctx, &config.AccessLogConfig{}, 0, http.StatusOK, req)
+	require.Equal(t, "/items/1?api_key=[REDACTED]&full=true", logger.LastEntry().Fields["request"])
+
+	serveAccessLogged(ctx, &config.AccessLogConfig{Format: config.AccessLogFormatCombined}, 0, http.StatusOK, req)
+	message := logger.LastEntry().Message
+	require.Contains(t, message, `"GET /items/1?api_key=[REDACTED]&full=true HTTP/1.1"`)
+	require.NotContains(t, message, "secret")
+}
+
+func TestAccessLogMiddlewareLevels(t *testing.T) {
+	for _, tt := range []struct {
+		status             int
+		clientErrorsAsInfo bool
+		level              log.Level
+	}{
+		{http.StatusOK, false, log.InfoLevel},
+		{http.StatusNotFound, false, log.ErrorLevel},
+		{http.StatusNotFound, true, log.InfoLevel},
+		{http.StatusInternalServerError, true, log.ErrorLevel},
+	} {
+		ctx, logger := testutil.NewTestContextWithLogger()
+		cfg := &config.AccessLogConfig{ClientErrorsAsInfo: tt.clientErrorsAsInfo}
+		serveAccessLogged(ctx, cfg, 0, tt.status, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+		require.Equal(t, 1, logger.EntryCount())
+		require.Equal(t, tt.level, logger.LastEntry().Level, "status %d", tt.status)
+	}
+}
+
+func TestAccessLogMiddlewareSampling(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	cfg := &config.AccessLogConfig{SampleRate: 0.5}
+
+	serveAccessLogged(ctx, cfg, 0.7, http.StatusOK, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	require.Equal(t, 0, logger.EntryCount())
+
+	serveAccessLogged(ctx, cfg, 0.2, http.StatusOK, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	require.Equal(t, 1, logger.EntryCount())
+
+	// Failed requests are always logged.
+	serveAccessLogged(ctx, cfg, 0.7, http.StatusBadGateway, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	require.Equal(t, 2, logger.EntryCount())
+}
+
+func TestAccessLogMiddlewarePanic(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	router := chi.NewRouter()
+	// Recover from the panic outside the access log, as the Recoverer middleware does.
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if recover() != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Use(newAccessLogMiddleware(&config.AccessLogConfig{}, func() float64 { return 0 }))
+	router.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil).WithContext(ctx))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, 1, logger.EntryCount())
+	entry := logger.LastEntry()
+	require.Equal(t, log.ErrorLevel, entry.Level)
+	require.Equal(t, http.StatusInternalServerError, entry.Fields["status"])
+}
+
+func TestCoreRequestContextMiddlewareWithAccessLog(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger(testutil.WithConfig(&config.DefaultConfig{
+		Library: config.LibraryConfig{Log: config.LogConfig{AccessLog: &config.AccessLogConfig{}}},
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	CoreRequestContextMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), req)
+
+	// The request is logged by the access log middleware rather than the request timer.
+	require.Equal(t, 0, logger.EntryCount())
+}
+
+func TestAccessLogMiddlewareLogsTraceID(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger(testutil.WithConfig(&config.DefaultConfig{
+		Library: config.LibraryConfig{Log: config.LogConfig{AccessLog: &config.AccessLogConfig{}}},
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	handler := AccessLogMiddleware(&config.AccessLogConfig{})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	CoreRequestContextMiddleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	// The trace ID is added to the logger of every request by CoreRequestContextMiddleware.
+	require.Equal(t, 1, logger.EntryCount())
+	require.NotEmpty(t, logger.LastEntry().Fields[traceIDLogField])
+}
@@ -0,0 +1,263 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/log"
+)
+
+// ErrCircuitOpen is the cause of the errors returned for requests rejected by an open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// The states of a circuit breaker, also the values of the downstream_circuit_breaker_state metric.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitHalfOpen
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	circuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "downstream_circuit_breaker_state",
+			Help: "State of the circuit breaker of each downstream (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"downstream"},
+	)
+	circuitBreakerTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "downstream_circuit_breaker_transitions_total",
+			Help: "Circuit breaker state transitions, by downstream and new state",
+		},
+		[]string{"downstream", "state"},
+	)
+	circuitBreakerRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "downstream_circuit_breaker_rejections_total",
+			Help: "Requests rejected by an open circuit breaker, by downstream",
+		},
+		[]string{"downstream"},
+	)
+)
+
+// CircuitBreakerCollectors returns the collectors of the circuit breaker metrics, for registration
+// against a prometheus registry.
+func CircuitBreakerCollectors() []prometheus.Collector {
+	return []prometheus.Collector{circuitBreakerState, circuitBreakerTransitions, circuitBreakerRejections}
+}
+
+// CircuitBreaker tracks the failures of requests to a downstream and rejects requests while the
+// downstream is deemed unavailable (see config.CircuitBreakerConfig).
+type CircuitBreaker struct {
+	name string
+	cfg  config.CircuitBreakerConfig
+	now  func() time.Time
+
+	m          sync.Mutex
+	state      CircuitState
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	inFlight   int
+	successes  int
+}
+
+// NewCircuitBreaker returns a closed circuit breaker for the named downstream.
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	circuitBreakerState.WithLabelValues(name).Set(float64(CircuitClosed))
+	return &CircuitBreaker{name: name, cfg: cfg.WithDefaults(), now: time.Now}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() CircuitState {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.openExpired() {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a request may be made. If so, the returned done func must be called with
+// the outcome of the request: true for success, false for failure, or nil if the outcome says
+// nothing about the health of the downstream (e.g. the request was cancelled by the caller).
+func (b *CircuitBreaker) Allow(ctx context.Context) (done func(success *bool), err error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.expireOpen(ctx)
+	switch b.state {
+	case CircuitOpen:
+		circuitBreakerRejections.WithLabelValues(b.name).Inc()
+		return nil, &ServerError{Kind: DownstreamUnavailableError, Message: fmt.Sprintf("circuit breaker for %s is open", b.name), Cause: ErrCircuitOpen}
+	case CircuitHalfOpen:
+		if b.inFlight >= b.cfg.HalfOpenMaxRequests {
+			circuitBreakerRejections.WithLabelValues(b.name).Inc()
+			return nil, &ServerError{Kind: DownstreamUnavailableError, Message: fmt.Sprintf("circuit breaker for %s is half-open", b.name), Cause: ErrCircuitOpen}
+		}
+		b.inFlight++
+	}
+
+	generation := b.generation
+	return func(success *bool) { b.record(ctx, generation, success) }, nil
+}
+
+// record records the outcome of a request allowed in the given generation. Outcomes of requests
+// allowed before the last transition are ignored.
+func (b *CircuitBreaker) record(ctx context.Context, generation uint64, success *bool) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	if b.state == CircuitHalfOpen {
+		b.inFlight--
+	}
+	if success == nil {
+		return
+	}
+
+	switch {
+	case b.state == CircuitClosed && *success:
+		b.failures = 0
+	case b.state == CircuitClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.transition(ctx, CircuitOpen)
+		}
+	case b.state == CircuitHalfOpen && *success:
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenMaxRequests {
+			b.transition(ctx, CircuitClosed)
+		}
+	case b.state == CircuitHalfOpen:
+		b.transition(ctx, CircuitOpen)
+	}
+}
+
+// expireOpen moves an open breaker to half-open once the open timeout has elapsed.
+func (b *CircuitBreaker) expireOpen(ctx context.Context) {
+	if b.openExpired() {
+		b.transition(ctx, CircuitHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) openExpired() bool {
+	return b.state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.cfg.OpenTimeout))
+}
+
+func (b *CircuitBreaker) transition(ctx context.Context, state CircuitState) {
+	log.Infof(ctx, "circuit breaker for %s changed from %s to %s", b.name, b.state, state)
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.inFlight = 0
+	b.successes = 0
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+	circuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+	circuitBreakerTransitions.WithLabelValues(b.name, state.String()).Inc()
+}
+
+type circuitBreakerRoundTripper struct {
+	breaker *CircuitBreaker
+	base    http.RoundTripper
+}
+
+// NewCircuitBreakerRoundTripper returns a round-tripper that guards the given base round-tripper with
+// a circuit breaker for the named downstream. Errors and 5xx responses count as failures. Requests
+// rejected by the breaker fail with a DownstreamUnavailableError.
+func NewCircuitBreakerRoundTripper(name string, cfg config.CircuitBreakerConfig, base http.RoundTripper) http.RoundTripper {
+	return &circuitBreakerRoundTripper{breaker: NewCircuitBreaker(name, cfg), base: base}
+}
+
+func (t *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := t.breaker.Allow(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && req.Context().Err() == context.Canceled {
+		done(nil)
+		return resp, err
+	}
+	success := err == nil && resp.StatusCode < http.StatusInternalServerError
+	done(&success)
+	return resp, err
+}
+
+// circuitBreakerFailureCodes are the gRPC status codes counted as failures by a circuit breaker.
+var circuitBreakerFailureCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+}
+
+// NewCircuitBreakerUnaryClientInterceptor returns a unary client interceptor that guards calls with a
+// circuit breaker for the named downstream. Calls failing with the Unavailable, DeadlineExceeded or
+// ResourceExhausted status codes count as failures. Calls rejected by the breaker fail with the
+// Unavailable status code and a DownstreamUnavailableError whose cause is ErrCircuitOpen.
+func NewCircuitBreakerUnaryClientInterceptor(name string, cfg config.CircuitBreakerConfig) grpc.UnaryClientInterceptor {
+	breaker := NewCircuitBreaker(name, cfg)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done, err := breaker.Allow(ctx)
+		if err != nil {
+			return &circuitOpenStatusError{err}
+		}
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil && ctx.Err() == context.Canceled {
+			done(nil)
+			return err
+		}
+		success := !circuitBreakerFailureCodes[status.Code(err)]
+		done(&success)
+		return err
+	}
+}
+
+// circuitOpenStatusError is the error of a gRPC call rejected by a circuit breaker: an Unavailable
+// status that wraps the error of the breaker, so that ErrCircuitOpen can still be recognised.
+type circuitOpenStatusError struct {
+	err error
+}
+
+func (e *circuitOpenStatusError) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+func (e *circuitOpenStatusError) Unwrap() error {
+	return e.err
+}
+
+func (e *circuitOpenStatusError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.err.Error())
+}
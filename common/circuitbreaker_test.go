@@ -0,0 +1,161 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/testutil"
+)
+
+func newTestCircuitBreaker(cfg config.CircuitBreakerConfig) (*CircuitBreaker, *time.Time) {
+	now := time.Now()
+	b := NewCircuitBreaker("test", cfg)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func requireRejected(t *testing.T, err error) {
+	var serverErr *ServerError
+	require.True(t, errors.As(err, &serverErr))
+	require.Equal(t, DownstreamUnavailableError, serverErr.Kind)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+}
+
+func outcome(success bool) *bool {
+	return &success
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestCircuitBreaker(config.CircuitBreakerConfig{FailureThreshold: 3})
+
+	for i := 0; i < 2; i++ {
+		done, err := b.Allow(testutil.NewTestContext())
+		require.NoError(t, err)
+		done(outcome(false))
+	}
+	// A success resets the count of consecutive failures.
+	done, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done(outcome(true))
+	for i := 0; i < 3; i++ {
+		require.Equal(t, CircuitClosed, b.State())
+		done, err := b.Allow(testutil.NewTestContext())
+		require.NoError(t, err)
+		done(outcome(false))
+	}
+
+	require.Equal(t, CircuitOpen, b.State())
+	_, err = b.Allow(testutil.NewTestContext())
+	requireRejected(t, err)
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b, now := newTestCircuitBreaker(config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenMaxRequests: 2})
+	done, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done(outcome(false))
+	require.Equal(t, CircuitOpen, b.State())
+
+	*now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, b.State())
+
+	// Only HalfOpenMaxRequests trial requests are let through.
+	done1, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done2, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	_, err = b.Allow(testutil.NewTestContext())
+	requireRejected(t, err)
+
+	done1(outcome(true))
+	require.Equal(t, CircuitHalfOpen, b.State())
+	done2(outcome(true))
+	require.Equal(t, CircuitClosed, b.State())
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	b, now := newTestCircuitBreaker(config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	done, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done(outcome(false))
+
+	*now = now.Add(time.Minute)
+	done, err = b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done(outcome(false))
+	require.Equal(t, CircuitOpen, b.State())
+
+	*now = now.Add(time.Second)
+	_, err = b.Allow(testutil.NewTestContext())
+	requireRejected(t, err)
+}
+
+func TestCircuitBreakerIgnoresStaleOutcomes(t *testing.T) {
+	b, _ := newTestCircuitBreaker(config.CircuitBreakerConfig{FailureThreshold: 1})
+	stale, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done, err := b.Allow(testutil.NewTestContext())
+	require.NoError(t, err)
+	done(outcome(false))
+	require.Equal(t, CircuitOpen, b.State())
+
+	stale(outcome(true))
+	require.Equal(t, CircuitOpen, b.State())
+}
+
+func TestCircuitBreakerRoundTripper(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(http.StatusInternalServerError), response(http.StatusBadGateway), response(http.StatusOK)},
+		errs:      []error{nil, nil, nil},
+	}
+	rt := NewCircuitBreakerRoundTripper("test", config.CircuitBreakerConfig{FailureThreshold: 2}, base)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://localhost/", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req) //nolint:bodyclose // No response is returned.
+	require.Nil(t, resp)
+	requireRejected(t, err)
+	require.Len(t, base.bodies, 2)
+}
+
+func TestCircuitBreakerUnaryClientInterceptor(t *testing.T) {
+	interceptor := NewCircuitBreakerUnaryClientInterceptor("test", config.CircuitBreakerConfig{FailureThreshold: 2})
+	calls := 0
+	invoke := func(err error) error {
+		return interceptor(testutil.NewTestContext(), "/Test/Method", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				calls++
+				return err
+			})
+	}
+
+	// Errors other than those indicating an unavailable downstream are not failures.
+	require.Error(t, invoke(status.Error(codes.InvalidArgument, "bad")))
+	require.Error(t, invoke(status.Error(codes.Unavailable, "down")))
+	require.Error(t, invoke(status.Error(codes.NotFound, "missing")))
+	require.Error(t, invoke(status.Error(codes.Unavailable, "down")))
+	require.Error(t, invoke(status.Error(codes.DeadlineExceeded, "slow")))
+	require.Equal(t, 5, calls)
+
+	err := invoke(nil)
+	requireRejected(t, err)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, 5, calls)
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"context"
 	"net/http"
+	"net/url"
 
 	"github.com/anz-bank/sysl-go/common/internal"
 	"github.com/anz-bank/sysl-go/log"
@@ -29,6 +30,11 @@ type reqHeaderContext struct {
 	header http.Header
 }
 
+type reqMethodAndURLContext struct {
+	method string
+	url    *url.URL
+}
+
 type respHeaderAndStatusContext struct {
 	header http.Header
 	status int
@@ -60,6 +66,25 @@ func RequestHeaderFromContext(ctx context.Context) http.Header {
 	return reqHeader.header.Clone()
 }
 
+// RequestMethodAndURLToContext creates a new context containing the request method and URL.
+func RequestMethodAndURLToContext(ctx context.Context, method string, u *url.URL) context.Context {
+	var clone url.URL
+	if u != nil {
+		clone = *u
+	}
+	return context.WithValue(ctx, reqMethodAndURLContextKey{}, &reqMethodAndURLContext{method, &clone})
+}
+
+// RequestMethodAndURLFromContext retrieves the request method and URL from the context.
+func RequestMethodAndURLFromContext(ctx context.Context) (method string, u *url.URL) {
+	reqMethodAndURL, _ := ctx.Value(reqMethodAndURLContextKey{}).(*reqMethodAndURLContext)
+	if reqMethodAndURL == nil {
+		return "", nil
+	}
+	clone := *reqMethodAndURL.url
+	return reqMethodAndURL.method, &clone
+}
+
 // RespHeaderAndStatusToContext creates a new context containing the response header and status.
 func RespHeaderAndStatusToContext(ctx context.Context, header http.Header, status int) context.Context {
 	return context.WithValue(ctx, respHeaderAndStatusContextKey{}, &respHeaderAndStatusContext{header.Clone(), status})
@@ -92,6 +117,7 @@ func CoreRequestContextMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		ctx = log.WithStr(ctx, traceIDLogField, GetTraceIDFromContext(ctx).String())
+		ctx = RequestMethodAndURLToContext(ctx, r.Method, r.URL)
 
 		ctx = internal.AddResponseBodyMonitorToContext(ctx)
 		defer internal.CheckForUnclosedResponses(ctx)
@@ -101,15 +127,19 @@ func CoreRequestContextMiddleware(next http.Handler) http.Handler {
 
 		r = r.WithContext(ctx)
 
-		tl := internal.NewRequestTimer(w, r)
-		w = tl.RespWrapper
-		defer tl.Log(entry)
+		// The access log middleware, if configured, logs the completion of the request instead.
+		if !isAccessLogged(ctx) {
+			tl := internal.NewRequestTimer(w, r)
+			w = tl.RespWrapper
+			defer tl.Log(entry)
+		}
 
 		next.ServeHTTP(w, r)
 	})
 }
 
 type reqHeaderContextKey struct{}
+type reqMethodAndURLContextKey struct{}
 type respHeaderAndStatusContextKey struct{}
 
 func getReqHeaderContext(ctx context.Context) *reqHeaderContext {
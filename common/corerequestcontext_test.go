@@ -5,6 +5,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -82,6 +83,22 @@ func TestGetUnsetRequestHeader(t *testing.T) {
 	require.Equal(t, http.Header(nil), out)
 }
 
+func TestSetAndGetRequestMethodAndURL(t *testing.T) {
+	in, err := url.Parse("http://localhost/path?q=1")
+	require.NoError(t, err)
+
+	method, out := RequestMethodAndURLFromContext(RequestMethodAndURLToContext(context.Background(), http.MethodGet, in))
+	require.Equal(t, http.MethodGet, method)
+	require.Equal(t, in, out)
+	require.NotSame(t, in, out)
+}
+
+func TestGetUnsetRequestMethodAndURL(t *testing.T) {
+	method, out := RequestMethodAndURLFromContext(context.Background())
+	require.Empty(t, method)
+	require.Nil(t, out)
+}
+
 func TestGetUnsetResponseHeaderAndStatus(t *testing.T) {
 	header, status := RespHeaderAndStatusFromContext(context.Background())
 	require.Equal(t, http.Header(nil), header)
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/credauth"
+)
+
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders are the headers redacted in addition to those configured.
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", credauth.DefaultAPIKeyHeader}
+
+// payloadRedactor redacts logged payloads as configured by a config.PayloadRedactionConfig, along
+// with the header and query parameter that API keys are sent in.
+type payloadRedactor struct {
+	headers      map[string]bool
+	queryParams  map[string]bool
+	bodyFields   [][]string
+	maxBodySize  int
+	contentTypes []string
+}
+
+func newPayloadRedactor(cfg *config.PayloadRedactionConfig, apiKey *credauth.APIKeyConfig) *payloadRedactor {
+	if cfg == nil {
+		cfg = &config.PayloadRedactionConfig{}
+	}
+	headers := append(append([]string{}, defaultRedactedHeaders...), cfg.Headers...)
+	queryParams := cfg.QueryParams
+	if apiKey != nil {
+		if apiKey.Header != "" {
+			headers = append(headers, apiKey.Header)
+		}
+		if apiKey.QueryParam != "" {
+			queryParams = append(append([]string{}, queryParams...), apiKey.QueryParam)
+		}
+	}
+	p := &payloadRedactor{
+		headers:      map[string]bool{},
+		queryParams:  map[string]bool{},
+		maxBodySize:  cfg.MaxBodySize,
+		contentTypes: cfg.ContentTypes,
+	}
+	for _, h := range headers {
+		p.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, q := range queryParams {
+		p.queryParams[q] = true
+	}
+	for _, field := range cfg.BodyFields {
+		p.bodyFields = append(p.bodyFields, strings.Split(strings.TrimPrefix(field, "$."), "."))
+	}
+	return p
+}
+
+// header returns a copy of the header with the values of the redacted headers replaced.
+func (p *payloadRedactor) header(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name, values := range redacted {
+		if p.headers[http.CanonicalHeaderKey(name)] {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+	return redacted
+}
+
+// RedactURL returns a copy of the URL with the values of the query parameters redacted by the
+// config in the context replaced, for logging.
+func RedactURL(ctx context.Context, u *url.URL) *url.URL {
+	if u.RawQuery == "" {
+		redacted := *u
+		return &redacted
+	}
+	return configRedactor(config.GetDefaultConfig(ctx)).url(u)
+}
+
+// lastConfigRedactor holds the redactor of the config last given to configRedactor, which is
+// needed for each logged request, so that it is only built when the config changes.
+var lastConfigRedactor struct {
+	m        sync.Mutex
+	cfg      *config.DefaultConfig
+	redactor *payloadRedactor
+}
+
+// configRedactor returns the redactor of the payloads logged by the server with the given config.
+func configRedactor(cfg *config.DefaultConfig) *payloadRedactor {
+	lastConfigRedactor.m.Lock()
+	defer lastConfigRedactor.m.Unlock()
+	if lastConfigRedactor.redactor == nil || lastConfigRedactor.cfg != cfg {
+		lastConfigRedactor.cfg, lastConfigRedactor.redactor = cfg, newConfigRedactor(cfg)
+	}
+	return lastConfigRedactor.redactor
+}
+
+func newConfigRedactor(cfg *config.DefaultConfig) *payloadRedactor {
+	if cfg == nil {
+		return newPayloadRedactor(nil, nil)
+	}
+	var apiKey *credauth.APIKeyConfig
+	if cfg.Library.Authentication != nil {
+		apiKey = cfg.Library.Authentication.APIKey
+	}
+	return newPayloadRedactor(cfg.Library.Log.PayloadRedaction, apiKey)
+}
+
+// url returns a copy of the URL with the values of the redacted query parameters replaced. The
+// order and encoding of the other query parameters are kept.
+func (p *payloadRedactor) url(u *url.URL) *url.URL {
+	redacted := *u
+	if len(p.queryParams) == 0 || u.RawQuery == "" {
+		return &redacted
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key := strings.SplitN(param, "=", 2)[0]
+		if name, err := url.QueryUnescape(key); err == nil && p.queryParams[name] {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	redacted.RawQuery = strings.Join(params, "&")
+	return &redacted
+}
+
+// body returns the loggable form of a body with the given header.
+func (p *payloadRedactor) body(header http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	contentType := header.Get("Content-Type")
+	if !p.isLoggedContentType(contentType) {
+		return fmt.Sprintf("[omitted: content type %q]", contentType)
+	}
+	if len(p.bodyFields) > 0 {
+		masked, err := p.maskBody(body)
+		if err != nil {
+			return "[omitted: body is not JSON]"
+		}
+		body = masked
+	}
+	if p.maxBodySize > 0 && len(body) > p.maxBodySize {
+		return fmt.Sprintf("%s...[truncated %d bytes]", body[:p.maxBodySize], len(body)-p.maxBodySize)
+	}
+	return string(body)
+}
+
+func (p *payloadRedactor) isLoggedContentType(contentType string) bool {
+	if len(p.contentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range p.contentTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *payloadRedactor) maskBody(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	for _, path := range p.bodyFields {
+		value = maskPath(value, path)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// maskPath returns the value with the fields at the path masked.
+func maskPath(value interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return redactedValue
+	}
+	segment, rest := path[0], path[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if segment == "*" || segment == key {
+				v[key] = maskPath(field, rest)
+			}
+		}
+	case []interface{}:
+		if segment == "*" {
+			for i, element := range v {
+				v[i] = maskPath(element, rest)
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadRedactorHeader(t *testing.T) {
+	header := http.Header{
+		"Authorization": {"Bearer token"},
+		"Cookie":        {"session=1"},
+		"X-Api-Key":     {"key"},
+		"X-Client-Key":  {"key"},
+		"Accept":        {"application/json"},
+	}
+
+	redacted := newPayloadRedactor(nil, nil).header(header)
+	require.Equal(t, http.Header{
+		"Authorization": {redactedValue},
+		"Cookie":        {redactedValue},
+		"X-Api-Key":     {redactedValue},
+		"X-Client-Key":  {"key"},
+		"Accept":        {"application/json"},
+	}, redacted)
+	require.Equal(t, "Bearer token", header.Get("Authorization"))
+
+	redacted = newPayloadRedactor(
+		&config.PayloadRedactionConfig{Headers: []string{"accept"}},
+		&credauth.APIKeyConfig{Header: "X-Client-Key"},
+	).header(header)
+	require.Equal(t, http.Header{
+		"Authorization": {redactedValue},
+		"Cookie":        {redactedValue},
+		"X-Api-Key":     {redactedValue},
+		"X-Client-Key":  {redactedValue},
+		"Accept":        {redactedValue},
+	}, redacted)
+}
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("https://example.com/items?api_key=secret&page=2&token=a%20b&token=c&q=a%26b")
+	require.NoError(t, err)
+
+	require.Equal(t, u.String(), RedactURL(context.Background(), u).String())
+
+	cfg := &config.DefaultConfig{}
+	cfg.Library.Log.PayloadRedaction = &config.PayloadRedactionConfig{QueryParams: []string{"token"}}
+	cfg.Library.Authentication = &config.AuthenticationConfig{APIKey: &credauth.APIKeyConfig{QueryParam: "api_key"}}
+	redacted := RedactURL(config.PutDefaultConfig(context.Background(), cfg), u)
+	require.Equal(t, "https://example.com/items?api_key=[REDACTED]&page=2&token=[REDACTED]&token=[REDACTED]&q=a%26b", redacted.String())
+	require.Equal(t, "/items?api_key=[REDACTED]&page=2&token=[REDACTED]&token=[REDACTED]&q=a%26b", redacted.RequestURI())
+	require.Equal(t, "secret", u.Query().Get("api_key"))
+}
+
+func TestPayloadRedactorBody(t *testing.T) {
+	jsonHeader := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	p := newPayloadRedactor(&config.PayloadRedactionConfig{
+		BodyFields:   []string{"$.customer.email", "cards.*.number"},
+		ContentTypes: []string{"application/json", "text/*"},
+	}, nil)
+
+	body := `{"customer":{"email":"a@example.com","name":"A"},"cards":[{"number":1234,"type":"debit"}],"amount":1.50}`
+	require.Equal(t,
+		`{"amount":1.50,"cards":[{"number":"[REDACTED]","type":"debit"}],"customer":{"email":"[REDACTED]","name":"A"}}`,
+		p.body(jsonHeader, []byte(body)))
+
+	require.Equal(t, "[omitted: body is not JSON]", p.body(http.Header{"Content-Type": {"text/plain"}}, []byte("a@example.com")))
+	require.Equal(t, `[omitted: content type "image/png"]`, p.body(http.Header{"Content-Type": {"image/png"}}, []byte("png")))
+	require.Equal(t, "", p.body(jsonHeader, nil))
+
+	p = newPayloadRedactor(&config.PayloadRedactionConfig{MaxBodySize: 4}, nil)
+	require.Equal(t, "hell...[truncated 1 bytes]", p.body(http.Header{}, []byte("hello")))
+	require.Equal(t, "hell", p.body(http.Header{}, []byte("hell")))
+}
+
+func TestConfigRedactorIsReused(t *testing.T) {
+	cfg := &config.DefaultConfig{}
+	redactor := configRedactor(cfg)
+	require.Same(t, redactor, configRedactor(cfg))
+
+	other := &config.DefaultConfig{}
+	other.Library.Log.PayloadRedaction = &config.PayloadRedactionConfig{QueryParams: []string{"token"}}
+	require.NotSame(t, redactor, configRedactor(other))
+	require.True(t, configRedactor(other).queryParams["token"])
+}
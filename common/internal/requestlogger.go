@@ -32,6 +32,7 @@ type requestLogger struct {
 	protoMajor int
 	rw         http.ResponseWriter
 	flushed    bool
+	redactor   *payloadRedactor
 }
 
 func (r *requestLogger) LogResponse(resp *http.Response) {
@@ -69,10 +70,10 @@ func (r *requestLogger) FlushLog() {
 	ctx = log.WithStr(ctx, "logger", "common/internal/requestlogger.go")
 	ctx = log.WithStr(ctx, "func", "FlushLog()")
 
-	reqBody := r.req.body.String()
-	log.Debugf(ctx, "Request: header - %s\nbody[len:%v]: - %s", r.req.header, len(reqBody), reqBody)
-	respBody := r.resp.body.String()
-	log.Debugf(ctx, "Response: header - %s\nbody[len:%v]: - %s", r.resp.header, len(respBody), respBody)
+	reqBody := r.redactor.body(r.req.header, r.req.body.Bytes())
+	log.Debugf(ctx, "Request: header - %s\nbody[len:%v]: - %s", r.redactor.header(r.req.header), r.req.body.Len(), reqBody)
+	respBody := r.redactor.body(r.resp.header, r.resp.body.Bytes())
+	log.Debugf(ctx, "Response: header - %s\nbody[len:%v]: - %s", r.redactor.header(r.resp.header), r.resp.body.Len(), respBody)
 }
 
 type nopLogger struct{}
@@ -87,6 +88,7 @@ func NewRequestLogger(ctx context.Context, req *http.Request) (RequestLogger, co
 		l := &requestLogger{
 			ctx:        InitFieldsFromRequest(ctx, req),
 			protoMajor: req.ProtoMajor,
+			redactor:   configRedactor(cfg),
 		}
 		l.req.header = req.Header.Clone()
 		if req.Body != nil && req.Method != http.MethodGet {
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/log"
 
 	"github.com/anz-bank/sysl-go/testutil"
@@ -48,6 +49,28 @@ func TestLogger_FlushLog(t *testing.T) {
 	require.Equal(t, "Already flushed the request", logger.LastEntry().Message)
 }
 
+func TestRequestLogger_FlushLogRedacted(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger(
+		testutil.WithLogLevel(log.DebugLevel),
+		testutil.WithConfig(&config.DefaultConfig{Library: config.LibraryConfig{Log: config.LogConfig{
+			PayloadRedaction: &config.PayloadRedactionConfig{BodyFields: []string{"password"}},
+		}}}),
+		testutil.WithLogPayload(true))
+
+	req, err := http.NewRequest("POST", "http://example.com/foo", bytes.NewBufferString(`{"user":"a","password":"secret"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	l, _ := NewRequestLogger(ctx, req)
+	l.LogResponse(&http.Response{Header: http.Header{}, Body: ioutil.NopCloser(&bytes.Buffer{})})
+
+	require.Equal(t, 2, logger.EntryCount())
+	message := logger.Entries()[0].Message
+	require.NotContains(t, message, "Bearer token")
+	require.NotContains(t, message, "secret")
+	require.Contains(t, message, `{"password":"[REDACTED]","user":"a"}`)
+}
+
 func TestRequestLogger_NilBody(t *testing.T) {
 	ctx, _ := testutil.NewTestContextWithLogger()
 
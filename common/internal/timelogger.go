@@ -59,7 +59,7 @@ var distributedTracingIDs = []string{distributedTraceIDName, distributedSpanIDNa
 func InitFieldsFromRequest(ctx context.Context, req *http.Request) context.Context {
 	ctx = distributedTracingFields(ctx, req.Header)
 	ctx = log.WithStr(ctx, "remote", req.RemoteAddr)
-	ctx = log.WithStr(ctx, "request", req.URL.String())
+	ctx = log.WithStr(ctx, "request", RedactURL(ctx, req.URL).String())
 	ctx = log.WithStr(ctx, "method", req.Method)
 	return ctx
 }
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// PeerIdentity describes the verified client certificate of a mutual TLS connection.
+type PeerIdentity struct {
+	// Subject is the distinguished name of the certificate subject, e.g. "CN=client,O=Example".
+	Subject string
+	// DNSNames, URIs, EmailAddresses and IPAddresses are the subject alternative names of the
+	// certificate.
+	DNSNames       []string
+	URIs           []string
+	EmailAddresses []string
+	IPAddresses    []string
+	// SPIFFEID is the first URI SAN with the spiffe scheme, if any.
+	SPIFFEID string
+	// Certificate is the verified client certificate.
+	Certificate *x509.Certificate
+}
+
+// NewPeerIdentity returns the identity of the given certificate.
+func NewPeerIdentity(cert *x509.Certificate) *PeerIdentity {
+	id := &PeerIdentity{
+		Subject:        cert.Subject.String(),
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		Certificate:    cert,
+	}
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+		if id.SPIFFEID == "" && uri.Scheme == "spiffe" {
+			id.SPIFFEID = uri.String()
+		}
+	}
+	for _, ip := range cert.IPAddresses {
+		id.IPAddresses = append(id.IPAddresses, ip.String())
+	}
+	return id
+}
+
+// PeerIdentityFromTLS returns the identity of the verified client certificate of the given
+// connection, or nil if the client did not present a verified certificate.
+func PeerIdentityFromTLS(state *tls.ConnectionState) *PeerIdentity {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return NewPeerIdentity(state.VerifiedChains[0][0])
+}
+
+// SANs returns all the subject alternative names of the certificate.
+func (id *PeerIdentity) SANs() []string {
+	sans := make([]string, 0, len(id.DNSNames)+len(id.URIs)+len(id.EmailAddresses)+len(id.IPAddresses))
+	sans = append(sans, id.DNSNames...)
+	sans = append(sans, id.URIs...)
+	sans = append(sans, id.EmailAddresses...)
+	return append(sans, id.IPAddresses...)
+}
+
+type peerIdentityContextKey struct{}
+
+// PeerIdentityToContext creates a new context containing the identity of the TLS peer.
+func PeerIdentityToContext(ctx context.Context, id *PeerIdentity) context.Context {
+	return context.WithValue(ctx, peerIdentityContextKey{}, id)
+}
+
+// PeerIdentityFromContext retrieves the identity of the TLS peer from the context, or nil if the
+// request was not made over mutual TLS.
+func PeerIdentityFromContext(ctx context.Context) *PeerIdentity {
+	id, _ := ctx.Value(peerIdentityContextKey{}).(*PeerIdentity)
+	return id
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPeerCertificate(t *testing.T) *x509.Certificate {
+	spiffeID, err := url.Parse("spiffe://example.com/client")
+	require.NoError(t, err)
+	other, err := url.Parse("https://example.com/client")
+	require.NoError(t, err)
+	return &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client", Organization: []string{"Example"}},
+		DNSNames:       []string{"client.example.com"},
+		URIs:           []*url.URL{other, spiffeID},
+		EmailAddresses: []string{"client@example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+	}
+}
+
+func TestNewPeerIdentity(t *testing.T) {
+	cert := testPeerCertificate(t)
+	id := NewPeerIdentity(cert)
+
+	require.Equal(t, "CN=client,O=Example", id.Subject)
+	require.Equal(t, "spiffe://example.com/client", id.SPIFFEID)
+	require.Equal(t, []string{
+		"client.example.com",
+		"https://example.com/client",
+		"spiffe://example.com/client",
+		"client@example.com",
+		"10.0.0.1",
+	}, id.SANs())
+	require.Same(t, cert, id.Certificate)
+}
+
+func TestPeerIdentityFromTLS(t *testing.T) {
+	require.Nil(t, PeerIdentityFromTLS(nil))
+	require.Nil(t, PeerIdentityFromTLS(&tls.ConnectionState{}))
+
+	// Unverified certificates are ignored.
+	cert := testPeerCertificate(t)
+	require.Nil(t, PeerIdentityFromTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}))
+
+	id := PeerIdentityFromTLS(&tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	})
+	require.NotNil(t, id)
+	require.Equal(t, "CN=client,O=Example", id.Subject)
+}
+
+func TestPeerIdentityContext(t *testing.T) {
+	ctx := context.Background()
+	require.Nil(t, PeerIdentityFromContext(ctx))
+
+	id := NewPeerIdentity(testPeerCertificate(t))
+	require.Same(t, id, PeerIdentityFromContext(PeerIdentityToContext(ctx, id)))
+}
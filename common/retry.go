@@ -0,0 +1,149 @@
+package common
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/log"
+)
+
+// idempotentMethods are the methods retried unless RetryConfig.RetryNonIdempotent is set.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+type retryRoundTripper struct {
+	cfg       config.RetryConfig
+	retryable map[int]bool
+	base      http.RoundTripper
+	sleep     func(d time.Duration) <-chan time.Time
+}
+
+// NewRetryRoundTripper returns a round-tripper that retries failed requests made through the given
+// base round-tripper as described by the given config (see config.RetryConfig).
+func NewRetryRoundTripper(cfg config.RetryConfig, base http.RoundTripper) http.RoundTripper {
+	cfg = cfg.WithDefaults()
+	retryable := make(map[int]bool, len(cfg.RetryableStatusCodes))
+	for _, code := range cfg.RetryableStatusCodes {
+		retryable[code] = true
+	}
+	return &retryRoundTripper{
+		cfg:       cfg,
+		retryable: retryable,
+		base:      base,
+		sleep:     time.After,
+	}
+}
+
+func (t *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := t.cfg.MaxAttempts
+	if !t.cfg.RetryNonIdempotent && !idempotentMethods[req.Method] {
+		attempts = 1
+	}
+	// A request body that cannot be re-read cannot be retried.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		attempts = 1
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= attempts || !t.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+
+		log.Infof(ctx, "retrying %s %s after %s (attempt %d of %d)", req.Method, req.URL.Redacted(), delay, attempt+1, attempts)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.sleep(delay):
+		}
+	}
+}
+
+func (t *retryRoundTripper) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return t.retryable[resp.StatusCode]
+}
+
+// backoff returns the wait before the given retry, taken from the Retry-After header of the
+// response if present, or else computed by exponential backoff with jitter. The wait is capped by
+// the configured maximum backoff.
+func (t *retryRoundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil && !t.cfg.IgnoreRetryAfter {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > t.cfg.MaxBackoff {
+				d = t.cfg.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	backoff := float64(t.cfg.InitialBackoff) * math.Pow(t.cfg.Multiplier, float64(attempt-1))
+	if backoff > float64(t.cfg.MaxBackoff) {
+		backoff = float64(t.cfg.MaxBackoff)
+	}
+	backoff -= backoff * *t.cfg.Jitter * rand.Float64() //nolint:gosec // Jitter need not be cryptographically secure.
+	return time.Duration(backoff)
+}
+
+// retryAfter parses the value of a Retry-After header, which holds either a number of seconds or
+// an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := time.Until(at)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func drainAndClose(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	_ = body.Close()
+}
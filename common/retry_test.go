@@ -0,0 +1,190 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type scriptedRoundTripper struct {
+	responses []*http.Response
+	errs      []error
+	bodies    []string
+}
+
+func (s *scriptedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := len(s.bodies)
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	s.bodies = append(s.bodies, body)
+	if s.errs[i] != nil {
+		return nil, s.errs[i]
+	}
+	return s.responses[i], nil
+}
+
+func response(code int, header ...string) *http.Response {
+	resp := &http.Response{StatusCode: code, Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+	for i := 0; i+1 < len(header); i += 2 {
+		resp.Header.Set(header[i], header[i+1])
+	}
+	return resp
+}
+
+func newTestRetryRoundTripper(cfg config.RetryConfig, base http.RoundTripper) (*retryRoundTripper, *[]time.Duration) {
+	var delays []time.Duration
+	rt := NewRetryRoundTripper(cfg, base).(*retryRoundTripper)
+	rt.sleep = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		c := make(chan time.Time, 1)
+		c <- time.Now()
+		return c
+	}
+	return rt, &delays
+}
+
+func TestRetryRoundTripper_RetriesUntilSuccess(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{nil, response(503), response(200)},
+		errs:      []error{errors.New("connection reset"), nil, nil},
+	}
+	noJitter := 0.0
+	rt, delays := newTestRetryRoundTripper(config.RetryConfig{InitialBackoff: time.Second, Jitter: &noJitter}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodPut, "http://example.com", bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, []string{"payload", "payload", "payload"}, base.bodies)
+	require.Len(t, *delays, 2)
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+}
+
+func TestRetryRoundTripper_GivesUpAfterMaxAttempts(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(502), response(502)},
+		errs:      []error{nil, nil},
+	}
+	rt, _ := newTestRetryRoundTripper(config.RetryConfig{MaxAttempts: 2}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 502, resp.StatusCode)
+	require.Len(t, base.bodies, 2)
+}
+
+func TestRetryRoundTripper_NonIdempotentNotRetriedByDefault(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(503), response(200)},
+		errs:      []error{nil, nil},
+	}
+	rt, _ := newTestRetryRoundTripper(config.RetryConfig{}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodPost, "http://example.com", bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 503, resp.StatusCode)
+	require.Len(t, base.bodies, 1)
+
+	base.bodies = nil
+	rt, _ = newTestRetryRoundTripper(config.RetryConfig{RetryNonIdempotent: true}, base)
+	req, err = http.NewRequestWithContext(testutil.NewTestContext(), http.MethodPost, "http://example.com", bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	require.Len(t, base.bodies, 2)
+}
+
+func TestRetryRoundTripper_NonRetryableStatus(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(500), response(200)},
+		errs:      []error{nil, nil},
+	}
+	rt, _ := newTestRetryRoundTripper(config.RetryConfig{}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 500, resp.StatusCode)
+	require.Len(t, base.bodies, 1)
+}
+
+func TestRetryRoundTripper_HonoursRetryAfter(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(429, "Retry-After", "7"), response(200)},
+		errs:      []error{nil, nil},
+	}
+	rt, delays := newTestRetryRoundTripper(config.RetryConfig{MaxBackoff: 10 * time.Second}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, []time.Duration{7 * time.Second}, *delays)
+}
+
+func TestRetryRoundTripper_CapsRetryAfter(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(503, "Retry-After", "3600"), response(200)},
+		errs:      []error{nil, nil},
+	}
+	rt, delays := newTestRetryRoundTripper(config.RetryConfig{}, base)
+
+	req, err := http.NewRequestWithContext(testutil.NewTestContext(), http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, []time.Duration{config.DefaultRetryMaxBackoff}, *delays)
+}
+
+func TestRetryRoundTripper_RespectsContextDeadline(t *testing.T) {
+	base := &scriptedRoundTripper{
+		responses: []*http.Response{response(503, "Retry-After", "60"), response(200)},
+		errs:      []error{nil, nil},
+	}
+	rt, delays := newTestRetryRoundTripper(config.RetryConfig{}, base)
+
+	ctx, cancel := context.WithTimeout(testutil.NewTestContext(), time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, 503, resp.StatusCode)
+	require.Len(t, base.bodies, 1)
+	require.Empty(t, *delays)
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("3")
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, d)
+
+	d, ok = retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.InDelta(t, time.Hour, d, float64(2*time.Second))
+
+	_, ok = retryAfter("soon")
+	require.False(t, ok)
+	_, ok = retryAfter("")
+	require.False(t, ok)
+}
@@ -2,14 +2,19 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/log"
 
 	"github.com/anz-bank/sysl-go/common/internal"
+	"github.com/anz-bank/sysl-go/tracing"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type traceabilityContextKey struct{}
@@ -22,19 +27,44 @@ type requestID struct {
 const traceIDLogField = "traceid"
 const defaultIncomingHeaderForID = "RequestID"
 
-// Injects a traceId UUID into the request context.
+// Injects a traceId UUID into the request context and records a server span for the request.
+//
+// The span continues the trace propagated in the request headers (see tracing.ParsePropagators),
+// else the trace identified by the RequestID header, else a new trace. The traceId is the trace ID of
+// the span.
 func TraceabilityMiddleware(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		val, err := uuid.Parse(r.Header.Get(getIncomingHeaderForID(ctx)))
-		if err != nil {
-			log.Info(internal.InitFieldsFromRequest(ctx, r), "Incoming request with invalid or missing RequestID header, filled traceid with new UUID instead")
-			r = r.WithContext(AddTraceIDToContext(r.Context(), uuid.New(), false))
-		} else {
-			r = r.WithContext(AddTraceIDToContext(r.Context(), val, true))
+		tracer := tracing.GetTracer(ctx)
+		ctx = tracer.Extract(ctx, tracing.HeaderCarrier(r.Header))
+		wasProvided := tracing.SpanContextFromContext(ctx).IsValid()
+		if !wasProvided {
+			val, err := uuid.Parse(r.Header.Get(getIncomingHeaderForID(ctx)))
+			if err != nil {
+				log.Info(internal.InitFieldsFromRequest(ctx, r), "Incoming request with invalid or missing RequestID header, filled traceid with new UUID instead")
+			} else {
+				ctx = tracing.ContextWithRemoteSpanContext(ctx, tracing.SpanContext{TraceID: tracing.TraceID(val), Sampled: true})
+				wasProvided = true
+			}
 		}
 
-		next.ServeHTTP(w, r)
+		ctx, span := tracer.Start(ctx, fmt.Sprintf("HTTP %s", r.Method), tracing.SpanKindServer)
+		defer span.End()
+		span.SetAttribute(tracing.AttributeHTTPMethod, r.Method)
+		span.SetAttribute(tracing.AttributeHTTPTarget, internal.RedactURL(ctx, r.URL).RequestURI())
+		ctx = AddTraceIDToContext(ctx, uuid.UUID(span.SpanContext().TraceID), wasProvided)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttribute(tracing.AttributeHTTPStatusCode, status)
+		if status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("status %d", status))
+		}
 	}
 	return http.HandlerFunc(fn)
 }
@@ -66,3 +96,61 @@ func getIncomingHeaderForID(ctx context.Context) string {
 
 	return ret
 }
+
+func getOutgoingHeaderForID(ctx context.Context) string {
+	cfg := config.GetDefaultConfig(ctx)
+	if cfg != nil && cfg.Library.Trace.OutgoingHeaderForID != "" {
+		return cfg.Library.Trace.OutgoingHeaderForID
+	}
+
+	return getIncomingHeaderForID(ctx)
+}
+
+type traceIDRoundTripper struct {
+	base http.RoundTripper
+}
+
+// NewTraceIDRoundTripper returns a round-tripper that writes the trace ID held by the request context
+// (see AddTraceIDToContext) to the outgoing header for ID (library.trace.outgoingHeaderForID, which
+// defaults to library.trace.incomingHeaderForID) of each request made through the given base
+// round-tripper.
+func NewTraceIDRoundTripper(base http.RoundTripper) http.RoundTripper {
+	return &traceIDRoundTripper{base: base}
+}
+
+func (t *traceIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if val, ok := ctx.Value(traceabilityContextKey{}).(*requestID); ok {
+		// Requests must not be modified by round-trippers, so set the header on a copy.
+		req = req.Clone(ctx)
+		req.Header.Set(getOutgoingHeaderForID(ctx), val.id.String())
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// TraceIDUnaryClientInterceptor writes the trace ID held by the context (see AddTraceIDToContext) to
+// the outgoing metadata of each call, under the outgoing header for ID (see NewTraceIDRoundTripper).
+func TraceIDUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if val, ok := ctx.Value(traceabilityContextKey{}).(*requestID); ok {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set(getOutgoingHeaderForID(ctx), val.id.String())
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// TraceIDStreamClientInterceptor writes the trace ID held by the context to the outgoing metadata of
+// each stream, as TraceIDUnaryClientInterceptor does for unary calls.
+func TraceIDStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if val, ok := ctx.Value(traceabilityContextKey{}).(*requestID); ok {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set(getOutgoingHeaderForID(ctx), val.id.String())
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	return streamer(ctx, desc, cc, method, opts...)
+}
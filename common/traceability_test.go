@@ -2,14 +2,20 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/anz-bank/sysl-go/tracing"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 //nolint:maligned // This is just a test and it's easier to read in this order
@@ -65,3 +71,96 @@ func TestTraceabilityMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestTraceabilityMiddlewareSpan(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	ctx, logger := testutil.NewTestContextWithLogger()
+	ctx = tracing.PutTracer(ctx, tracing.NewTracer(exporter, nil))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/path?q=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	var traceID string
+	fn := TraceabilityMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, wasProvided := TryGetTraceIDFromContext(r.Context())
+		require.True(t, wasProvided)
+		traceID = id.String()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	fn.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Zero(t, logger.EntryCount())
+	require.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", traceID)
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "HTTP GET", spans[0].Name)
+	require.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID.String())
+	require.Equal(t, "/path?q=1", spans[0].Attributes[tracing.AttributeHTTPTarget])
+	require.Equal(t, http.StatusInternalServerError, spans[0].Attributes[tracing.AttributeHTTPStatusCode])
+	require.Error(t, spans[0].Err)
+}
+
+func TestTraceabilityMiddlewareSpanFromRequestID(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	ctx := tracing.PutTracer(testutil.NewTestContext(), tracing.NewTracer(exporter, nil))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.Header.Set(defaultIncomingHeaderForID, "652817bc-ee0c-40e3-936c-fa74aea0ad49")
+	fn := TraceabilityMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	fn.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "652817bcee0c40e3936cfa74aea0ad49", spans[0].SpanContext.TraceID.String())
+	require.False(t, spans[0].ParentSpanID.IsValid())
+	require.Equal(t, http.StatusOK, spans[0].Attributes[tracing.AttributeHTTPStatusCode])
+}
+
+func TestTraceIDRoundTripper(t *testing.T) {
+	id := uuid.MustParse("652817bc-ee0c-40e3-936c-fa74aea0ad49")
+	var header http.Header
+	rt := NewTraceIDRoundTripper(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		header = r.Header
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	cfg := config.DefaultConfig{}
+	cfg.Library.Trace.OutgoingHeaderForID = "X-Request-ID"
+	for _, tt := range []struct {
+		ctx      context.Context
+		expected http.Header
+	}{
+		{testutil.NewTestContext(), http.Header{}},
+		{AddTraceIDToContext(testutil.NewTestContext(), id, false), http.Header{"Requestid": {id.String()}}},
+		{AddTraceIDToContext(config.PutDefaultConfig(testutil.NewTestContext(), &cfg), id, true), http.Header{"X-Request-Id": {id.String()}}},
+	} {
+		req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, "http://localhost/", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, tt.expected, header)
+		require.Empty(t, req.Header)
+	}
+}
+
+func TestTraceIDUnaryClientInterceptor(t *testing.T) {
+	id := uuid.MustParse("652817bc-ee0c-40e3-936c-fa74aea0ad49")
+	ctx := metadata.AppendToOutgoingContext(AddTraceIDToContext(testutil.NewTestContext(), id, true), "other", "value")
+	err := TraceIDUnaryClientInterceptor(ctx, "/Test/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			require.Equal(t, []string{id.String()}, md.Get(defaultIncomingHeaderForID))
+			require.Equal(t, []string{"value"}, md.Get("other"))
+			return nil
+		})
+	require.NoError(t, err)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
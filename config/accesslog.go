@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Formats of an AccessLogConfig.
+const (
+	// AccessLogFormatJSON logs each request as a message with the request details as fields.
+	AccessLogFormatJSON = "json"
+	// AccessLogFormatCombined logs each request as a message in the Apache combined log format.
+	AccessLogFormatCombined = "combined"
+)
+
+// Optional fields of an AccessLogConfig.
+const (
+	AccessLogFieldUserAgent  = "userAgent"
+	AccessLogFieldBytesIn    = "bytesIn"
+	AccessLogFieldBytesOut   = "bytesOut"
+	AccessLogFieldRoute      = "route"
+	AccessLogFieldJWTSubject = "jwtSubject"
+)
+
+var accessLogFields = []string{
+	AccessLogFieldUserAgent,
+	AccessLogFieldBytesIn,
+	AccessLogFieldBytesOut,
+	AccessLogFieldRoute,
+	AccessLogFieldJWTSubject,
+}
+
+// AccessLogConfig configures the logging of a message for each completed HTTP request. When set,
+// it replaces the default "Request completed" log.
+type AccessLogConfig struct {
+	// Format is the format of the message: "json" (the default) or "combined".
+	Format string `yaml:"format" mapstructure:"format"`
+
+	// Fields are the optional fields included in messages of the json format, in addition to the
+	// method, request, status, remote address, latency and trace ID. One or more of userAgent,
+	// bytesIn, bytesOut, route and jwtSubject. The combined format has a fixed set of fields.
+	Fields []string `yaml:"fields" mapstructure:"fields"`
+
+	// SampleRate is the fraction, between 0 and 1, of successful requests that are logged.
+	// Requests that fail are always logged. Unset (or 0) logs every request.
+	SampleRate float64 `yaml:"sampleRate" mapstructure:"sampleRate"`
+
+	// ClientErrorsAsInfo logs requests that fail with a 4xx status at the info level rather than
+	// the error level.
+	ClientErrorsAsInfo bool `yaml:"clientErrorsAsInfo" mapstructure:"clientErrorsAsInfo"`
+}
+
+// HasField returns whether the given optional field is included in messages.
+func (c *AccessLogConfig) HasField(field string) bool {
+	return contains(c.Fields, field)
+}
+
+func (c *AccessLogConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Format {
+	case "", AccessLogFormatJSON, AccessLogFormatCombined:
+	default:
+		return fmt.Errorf("log.accessLog.format must be one of %s or %s", AccessLogFormatJSON, AccessLogFormatCombined)
+	}
+	for _, field := range c.Fields {
+		if !contains(accessLogFields, field) {
+			return fmt.Errorf("log.accessLog.fields: unknown field %s, must be one of %s", field, strings.Join(accessLogFields, ", "))
+		}
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return errors.New("log.accessLog.sampleRate must be between 0 and 1")
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogConfigValidate(t *testing.T) {
+	var nilCfg *AccessLogConfig
+	require.NoError(t, nilCfg.Validate())
+
+	cfg := &AccessLogConfig{
+		Format:     AccessLogFormatCombined,
+		Fields:     []string{AccessLogFieldUserAgent, AccessLogFieldJWTSubject},
+		SampleRate: 0.5,
+	}
+	require.NoError(t, cfg.Validate())
+	require.True(t, cfg.HasField(AccessLogFieldJWTSubject))
+	require.False(t, cfg.HasField(AccessLogFieldRoute))
+
+	cfg.Format = "apache"
+	require.EqualError(t, cfg.Validate(), "log.accessLog.format must be one of json or combined")
+
+	cfg.Format = AccessLogFormatJSON
+	cfg.Fields = []string{"referer"}
+	require.EqualError(t, cfg.Validate(), "log.accessLog.fields: unknown field referer, must be one of userAgent, bytesIn, bytesOut, route, jwtSubject")
+
+	cfg.Fields = nil
+	cfg.SampleRate = 1.5
+	require.EqualError(t, cfg.Validate(), "log.accessLog.sampleRate must be between 0 and 1")
+
+	lib := defaultConfig()
+	lib.Log.AccessLog = cfg
+	require.Error(t, lib.Validate())
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anz-bank/sysl-go/authexpr"
+)
+
+// Modes of an AuthorizationRuleConfig.
+const (
+	// AuthorizationRuleModeOverride replaces the authorization rule declared in the sysl specification.
+	AuthorizationRuleModeOverride = "override"
+	// AuthorizationRuleModeSupplement requires both the authorization rule declared in the sysl
+	// specification and the configured expression to grant access.
+	AuthorizationRuleModeSupplement = "supplement"
+)
+
+// AuthorizationConfig allows the authorization rules of endpoints to be changed without
+// regenerating the service.
+type AuthorizationConfig struct {
+	// Rules maps the names of endpoints (or gRPC methods) to their authorization rules. Only
+	// endpoints that have an authorization rule declared in the sysl specification can be configured.
+	// Names are matched case-insensitively, as config keys are not case-sensitive.
+	Rules map[string]AuthorizationRuleConfig `yaml:"rules" mapstructure:"rules"`
+}
+
+// AuthorizationRuleConfig struct.
+type AuthorizationRuleConfig struct {
+	// Expression is the authorization rule expression, see the authexpr package.
+	Expression string `yaml:"expression" mapstructure:"expression"`
+	// Mode is how the expression is combined with the declared rule: "override" (the default) or
+	// "supplement".
+	Mode string `yaml:"mode" mapstructure:"mode"`
+}
+
+// Rule returns the effective authorization rule expression of the named endpoint, given the rule
+// expression declared in the sysl specification.
+func (c *AuthorizationConfig) Rule(endpointName string, declared string) string {
+	if c == nil {
+		return declared
+	}
+	rule, ok := c.lookup(endpointName)
+	if !ok {
+		return declared
+	}
+	if rule.Mode == AuthorizationRuleModeSupplement {
+		return fmt.Sprintf("all(%s,%s)", declared, rule.Expression)
+	}
+	return rule.Expression
+}
+
+// lookup returns the configured rule of the named endpoint, matching the name case-insensitively.
+func (c *AuthorizationConfig) lookup(endpointName string) (AuthorizationRuleConfig, bool) {
+	if rule, ok := c.Rules[endpointName]; ok {
+		return rule, true
+	}
+	for name, rule := range c.Rules {
+		if strings.EqualFold(name, endpointName) {
+			return rule, true
+		}
+	}
+	return AuthorizationRuleConfig{}, false
+}
+
+func (c *AuthorizationConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Rules))
+	for name := range c.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rule := c.Rules[name]
+		switch rule.Mode {
+		case "", AuthorizationRuleModeOverride, AuthorizationRuleModeSupplement:
+		default:
+			return fmt.Errorf("authorization.rules.%s.mode must be one of %s or %s", name, AuthorizationRuleModeOverride, AuthorizationRuleModeSupplement)
+		}
+		if _, err := authexpr.CompileExpression(rule.Expression); err != nil {
+			return fmt.Errorf("authorization.rules.%s.expression: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationConfigRule(t *testing.T) {
+	cfg := &AuthorizationConfig{Rules: map[string]AuthorizationRuleConfig{
+		"GetA": {Expression: `jwtHasScope("a")`},
+		"GetB": {Expression: `jwtHasScope("b")`, Mode: AuthorizationRuleModeOverride},
+		"GetC": {Expression: `jwtHasScope("c")`, Mode: AuthorizationRuleModeSupplement},
+	}}
+
+	require.Equal(t, `jwtHasScope("a")`, cfg.Rule("GetA", `jwtHasScope("x")`))
+	require.Equal(t, `jwtHasScope("b")`, cfg.Rule("GetB", `jwtHasScope("x")`))
+	require.Equal(t, `all(jwtHasScope("x"),jwtHasScope("c"))`, cfg.Rule("GetC", `jwtHasScope("x")`))
+	require.Equal(t, `jwtHasScope("x")`, cfg.Rule("GetD", `jwtHasScope("x")`))
+	require.Equal(t, `jwtHasScope("a")`, cfg.Rule("geta", `jwtHasScope("x")`))
+
+	var nilCfg *AuthorizationConfig
+	require.Equal(t, `jwtHasScope("x")`, nilCfg.Rule("GetA", `jwtHasScope("x")`))
+}
+
+func TestAuthorizationConfigValidate(t *testing.T) {
+	var nilCfg *AuthorizationConfig
+	require.NoError(t, nilCfg.Validate())
+
+	cfg := &AuthorizationConfig{Rules: map[string]AuthorizationRuleConfig{
+		"GetA": {Expression: `any(jwtHasScope("a"), jwtIssuerIs("b"))`, Mode: AuthorizationRuleModeSupplement},
+	}}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Rules["GetB"] = AuthorizationRuleConfig{Expression: `jwtHasScope("b")`, Mode: "replace"}
+	require.EqualError(t, cfg.Validate(), "authorization.rules.GetB.mode must be one of override or supplement")
+
+	cfg.Rules["GetB"] = AuthorizationRuleConfig{Expression: `jwtHasScopes("b")`}
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "authorization.rules.GetB.expression: ")
+
+	lib := defaultConfig()
+	lib.Authorization = cfg
+	require.Error(t, lib.Validate())
+}
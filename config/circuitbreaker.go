@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults applied to a CircuitBreakerConfig by WithDefaults.
+const (
+	DefaultCircuitBreakerFailureThreshold    = 5
+	DefaultCircuitBreakerOpenTimeout         = 30 * time.Second
+	DefaultCircuitBreakerHalfOpenMaxRequests = 1
+)
+
+// CircuitBreakerConfig configures a circuit breaker in front of a downstream service. After
+// FailureThreshold consecutive failures the breaker opens and requests fail fast without reaching the
+// downstream. Once OpenTimeout has elapsed the breaker is half-open and lets up to HalfOpenMaxRequests
+// trial requests through: if they succeed the breaker closes, otherwise it opens again.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int `yaml:"failureThreshold" mapstructure:"failureThreshold"`
+	// OpenTimeout is how long the breaker stays open before letting trial requests through.
+	OpenTimeout time.Duration `yaml:"openTimeout" mapstructure:"openTimeout"`
+	// HalfOpenMaxRequests is the number of trial requests let through while half-open.
+	HalfOpenMaxRequests int `yaml:"halfOpenMaxRequests" mapstructure:"halfOpenMaxRequests"`
+}
+
+// WithDefaults returns a copy of the config with defaults set against any unset values.
+func (c CircuitBreakerConfig) WithDefaults() CircuitBreakerConfig {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if c.OpenTimeout == 0 {
+		c.OpenTimeout = DefaultCircuitBreakerOpenTimeout
+	}
+	if c.HalfOpenMaxRequests == 0 {
+		c.HalfOpenMaxRequests = DefaultCircuitBreakerHalfOpenMaxRequests
+	}
+	return c
+}
+
+func (c *CircuitBreakerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.FailureThreshold < 0 {
+		return fmt.Errorf("circuitBreaker.failureThreshold must not be negative")
+	}
+	if c.OpenTimeout < 0 {
+		return fmt.Errorf("circuitBreaker.openTimeout must not be negative")
+	}
+	if c.HalfOpenMaxRequests < 0 {
+		return fmt.Errorf("circuitBreaker.halfOpenMaxRequests must not be negative")
+	}
+	return nil
+}
@@ -13,6 +13,19 @@ type ConfigReader interface { // nolint:golint
 	Unmarshal(config interface{}) error
 }
 
+// ConfigSources lists the methods exposed by ConfigReaderImpl to report the config files that
+// have been read. Use a type assertion to access them from a ConfigReader.
+type ConfigSources interface { // nolint:golint
+	// Files returns the config files that have been read, in the order they were merged.
+	Files() []string
+
+	// Source returns the config file from which the effective value of the given key was read.
+	Source(key string) (string, bool)
+
+	// Sources returns the config file from which the effective value of each key was read.
+	Sources() map[string]string
+}
+
 // NilValueError is raised when the key value is nil.
 type NilValueError struct {
 	message string
@@ -105,6 +105,53 @@ func TestGetMultipleConfigFiles(t *testing.T) {
 	assert.Equal(t, "true", calleeLog)
 }
 
+func TestUnmarshalFromLayeredConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	type DemoConfig struct {
+		Foo int    `mapstructure:"foo"`
+		Bar int    `mapstructure:"bar"`
+		Baz string `mapstructure:"baz"`
+	}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "base.yaml", []byte("foo: 1\nbar: 2\nbaz: base"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "prod.yaml", []byte("bar: 20"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "secrets.yaml", []byte("baz: secret"), 0644))
+
+	reader := NewConfigReaderBuilder().WithFs(fs).WithStrictMode(true).
+		WithConfigFiles("base.yaml", "prod.yaml", "secrets.yaml").Build()
+
+	conf := DemoConfig{}
+	require.NoError(t, reader.Unmarshal(&conf))
+	assert.Equal(t, DemoConfig{Foo: 1, Bar: 20, Baz: "secret"}, conf)
+
+	sources, ok := reader.(ConfigSources)
+	require.True(t, ok)
+	assert.Equal(t, []string{"base.yaml", "prod.yaml", "secrets.yaml"}, sources.Files())
+	assert.Equal(t, map[string]string{"foo": "base.yaml", "bar": "prod.yaml", "baz": "secrets.yaml"}, sources.Sources())
+	source, ok := sources.Source("BAR")
+	assert.True(t, ok)
+	assert.Equal(t, "prod.yaml", source)
+	_, ok = sources.Source("missing")
+	assert.False(t, ok)
+}
+
+func TestConfigReaderBuilderCopiesDoNotShareSources(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "base.yaml", []byte("foo: 1"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "prod.yaml", []byte("bar: 2"), 0644))
+
+	base := NewConfigReaderBuilder().WithFs(fs).WithConfigFile("base.yaml")
+	prod := base.WithConfigFile("prod.yaml")
+
+	assert.Equal(t, []string{"base.yaml"}, base.Build().(ConfigSources).Files())
+	assert.Equal(t, map[string]string{"foo": "base.yaml"}, base.Build().(ConfigSources).Sources())
+	assert.Equal(t, []string{"base.yaml", "prod.yaml"}, prod.Build().(ConfigSources).Files())
+}
+
 func TestUnmarshalFromFileWithPrefix(t *testing.T) {
 	t.Parallel()
 
@@ -134,6 +181,18 @@ func TestUnmarshalFromFile(t *testing.T) {
 	assert.Equal(t, "https://bar.example.com", conf.Gencode.Downstream.Bar.ServiceURL)
 }
 
+func TestUnmarshalWarnLogLevel(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yaml", []byte("library:\n  log:\n    level: warn"), 0644))
+
+	conf := config{}
+	reader := NewConfigReaderBuilder().WithFs(fs).WithConfigFile("config.yaml").Build()
+	require.NoError(t, reader.Unmarshal(&conf))
+	assert.Equal(t, log.WarnLevel, conf.Library.Log.Level)
+}
+
 func TestUnmarshalSensitiveStringFromFile(t *testing.T) {
 	t.Parallel()
 
@@ -158,6 +217,28 @@ func TestUnmarshalSensitiveStringFromFile(t *testing.T) {
 	assert.Equal(t, "pwd2", conf.Password2.Value())
 }
 
+func TestUnmarshalJWTAuthSharedSecretFromFile(t *testing.T) {
+	t.Parallel()
+
+	var conf LibraryConfig
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "jwtauth_config.yaml", []byte(`
+authentication:
+  jwtauth:
+    issuers:
+      - name: test
+        sharedSecret: secret
+        cacheTTL: 1m
+`), 0644)
+	require.Nil(t, err)
+	reader := NewConfigReaderBuilder().WithFs(fs).WithConfigFile("jwtauth_config.yaml").Build()
+	require.NoError(t, reader.Unmarshal(&conf))
+	issuer := conf.Authentication.JWTAuth.Issuers[0]
+	assert.Equal(t, "secret", issuer.SharedSecret.Value())
+	assert.Equal(t, "****************", issuer.SharedSecret.String())
+	assert.Equal(t, time.Minute, time.Duration(issuer.CacheTTL))
+}
+
 func TestUnmarshalFromFileWithStrictMode(t *testing.T) {
 	t.Parallel()
 
@@ -10,9 +10,12 @@ import (
 
 // ConfigReaderBuilder exposes the builder api for configReaderImpl.
 // Use NewConfigReaderBuilder() and AttachEnvPrefix() to Build a ConfigReaderBuilder. Follow it up one or more calls
-// to WithConfigFile() and/or WithConfigName() and finally use Build() to Build the configReaderImpl.
+// to WithConfigFile(), WithConfigFiles() and/or WithConfigName() and finally use Build() to Build the configReaderImpl.
+// When more than one config file is attached the files are merged in the order they were attached, with values
+// from later files taking precedence over values from earlier files.
 type ConfigReaderBuilder struct { // nolint:golint
 	evarReader configReaderImpl
+	fs         afero.Fs
 }
 
 // NewConfigReaderBuilder builds a new ConfigReaderBuilder.
@@ -21,6 +24,7 @@ func NewConfigReaderBuilder() ConfigReaderBuilder {
 		evarReader: configReaderImpl{
 			envVars: viper.New(),
 		},
+		fs: afero.NewOsFs(),
 	}
 	return b
 }
@@ -39,6 +43,14 @@ func (b ConfigReaderBuilder) WithConfigFile(configFile string) ConfigReaderBuild
 	if err := b.evarReader.envVars.MergeInConfig(); err != nil {
 		log.Fatalln(err)
 	}
+	return b.recordSources(configFile)
+}
+
+// WithConfigFiles attaches each of the passed config files in order.
+func (b ConfigReaderBuilder) WithConfigFiles(configFiles ...string) ConfigReaderBuilder {
+	for _, configFile := range configFiles {
+		b = b.WithConfigFile(configFile)
+	}
 	return b
 }
 
@@ -51,12 +63,37 @@ func (b ConfigReaderBuilder) WithConfigName(configName string, configPath ...str
 	if err := b.evarReader.envVars.MergeInConfig(); err != nil {
 		log.Fatalln(err)
 	}
+	return b.recordSources(b.evarReader.envVars.ConfigFileUsed())
+}
+
+// recordSources records the given config file as the source of each of the keys it contains.
+// The recorded sources are copied rather than modified so that copies of the builder don't share
+// them.
+func (b ConfigReaderBuilder) recordSources(configFile string) ConfigReaderBuilder {
+	v := viper.New()
+	v.SetFs(b.fs)
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		log.Fatalln(err)
+	}
+	sources := sourceFiles{
+		files: append(append([]string{}, b.evarReader.sources.files...), configFile),
+		keys:  make(map[string]string, len(b.evarReader.sources.keys)),
+	}
+	for key, file := range b.evarReader.sources.keys {
+		sources.keys[key] = file
+	}
+	for _, key := range v.AllKeys() {
+		sources.keys[key] = configFile
+	}
+	b.evarReader.sources = sources
 	return b
 }
 
 // WithFs attaches the file system to use.
 func (b ConfigReaderBuilder) WithFs(fs afero.Fs) ConfigReaderBuilder {
 	b.evarReader.envVars.SetFs(fs)
+	b.fs = fs
 	return b
 }
 
@@ -78,8 +115,12 @@ func (b ConfigReaderBuilder) WithStrictMode(strict bool, ignoredKeys ...string)
 
 // Build Builds and returns the ConfigReader.
 func (b ConfigReaderBuilder) Build() ConfigReader {
-	if err := b.evarReader.envVars.ReadInConfig(); err != nil {
-		log.Fatalln(err)
+	// Config files that have been attached are already merged, reading the config again
+	// would discard all but the last of them.
+	if len(b.evarReader.sources.files) == 0 {
+		if err := b.evarReader.envVars.ReadInConfig(); err != nil {
+			log.Fatalln(err)
+		}
 	}
 	return b.evarReader
 }
@@ -20,6 +20,34 @@ type configReaderImpl struct {
 	envVars               *viper.Viper
 	strictMode            bool
 	strictModeIgnoredKeys []string
+	sources               sourceFiles
+}
+
+// sourceFiles records the config files that have been read and the file each key was last read from.
+type sourceFiles struct {
+	files []string
+	keys  map[string]string
+}
+
+// Files returns the config files that have been read, in the order they were merged.
+func (m configReaderImpl) Files() []string {
+	return append([]string{}, m.sources.files...)
+}
+
+// Source returns the config file from which the effective value of the given key was read.
+// Values read from environment variables are not reflected.
+func (m configReaderImpl) Source(key string) (string, bool) {
+	file, ok := m.sources.keys[strings.ToLower(key)]
+	return file, ok
+}
+
+// Sources returns the config file from which the effective value of each key was read.
+func (m configReaderImpl) Sources() map[string]string {
+	sources := make(map[string]string, len(m.sources.keys))
+	for key, file := range m.sources.keys {
+		sources[key] = file
+	}
+	return sources
 }
 
 // Get returns an interface{}.
@@ -137,7 +165,7 @@ func makeDefaultDecodeHook() mapstructure.DecodeHookFunc {
 			case "error":
 				return log.ErrorLevel, nil
 			case "warn":
-				return deprecated(str, log.InfoLevel)
+				return log.WarnLevel, nil
 			case "info":
 				return log.InfoLevel, nil
 			case "debug":
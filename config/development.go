@@ -6,4 +6,9 @@ type DevelopmentConfig struct {
 	// guarding calls to endpoints or RPC methods, and instead unconditionally grant access.
 	// This option is insecure and should not be enabled in production.
 	DisableAllAuthorizationRules bool `yaml:"disableAllAuthorizationRules" mapstructure:"disableAllAuthorizationRules"`
+
+	// explainAuthorizationDenials can be used to include the evaluation trace of the authorization
+	// rule expression in the responses to requests that are denied access. This option reveals the
+	// authorization rules and should not be enabled in production.
+	ExplainAuthorizationDenials bool `yaml:"explainAuthorizationDenials" mapstructure:"explainAuthorizationDenials"`
 }
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"net"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -17,6 +19,9 @@ func ExtractGrpcServerOptions(ctx context.Context, cfg *GRPCServerConfig) ([]grp
 		return nil, err
 	}
 
+	// NewTLS offers HTTP/2 by adding its protocol to a clone of the config, but the config of each
+	// handshake is built from this one when the trusted cert pool is reloaded.
+	tlsConfig.NextProtos = []string{"h2"}
 	creds := credentials.NewTLS(tlsConfig)
 
 	return []grpc.ServerOption{grpc.Creds(creds)}, nil
@@ -24,9 +29,10 @@ func ExtractGrpcServerOptions(ctx context.Context, cfg *GRPCServerConfig) ([]grp
 
 // CommonGRPCDownstreamData collects all the client gRPC configuration.
 type CommonGRPCDownstreamData struct {
-	ServiceAddress string     `yaml:"serviceAddress" mapstructure:"serviceAddress"`
-	TLS            *TLSConfig `yaml:"tls" mapstructure:"tls"`
-	WithBlock      bool       `yaml:"withBlock" mapstructure:"withBlock"`
+	ServiceAddress string                `yaml:"serviceAddress" mapstructure:"serviceAddress"`
+	TLS            *TLSConfig            `yaml:"tls" mapstructure:"tls"`
+	WithBlock      bool                  `yaml:"withBlock" mapstructure:"withBlock"`
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuitBreaker" mapstructure:"circuitBreaker"`
 }
 
 func NewDefaultCommonGRPCDownstreamData() *CommonGRPCDownstreamData {
@@ -42,11 +48,14 @@ func DefaultGrpcDialOptions(ctx context.Context, cfg *CommonGRPCDownstreamData)
 	}
 	var opts []grpc.DialOption
 	if cfg.TLS != nil {
-		tlsConfig, err := MakeTLSConfig(ctx, cfg.TLS)
+		tlsConfig, pool, err := makeTLSConfig(ctx, cfg.TLS)
 		if err != nil {
 			return nil, err
 		}
 		creds := credentials.NewTLS(tlsConfig)
+		if pool != nil {
+			creds = &reloadingCredentials{creds, tlsConfig, pool}
+		}
 		opts = append(opts, grpc.WithTransportCredentials(creds))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
@@ -56,3 +65,26 @@ func DefaultGrpcDialOptions(ctx context.Context, cfg *CommonGRPCDownstreamData)
 	}
 	return opts, nil
 }
+
+// reloadingCredentials are the TLS credentials of a gRPC client that trusts the reloaded trusted
+// cert pool (see TrustedCertPoolConfig.ReloadInterval).
+type reloadingCredentials struct {
+	credentials.TransportCredentials
+	settings *tls.Config
+	pool     *poolReloader
+}
+
+// ClientHandshake performs the handshake with credentials that trust the current pool.
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.pool.clientConfig(c.settings)).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{c.TransportCredentials.Clone(), c.settings.Clone(), c.pool}
+}
+
+func (c *reloadingCredentials) OverrideServerName(serverNameOverride string) error {
+	c.settings = c.settings.Clone()
+	c.settings.ServerName = serverNameOverride
+	return c.TransportCredentials.OverrideServerName(serverNameOverride) //nolint:staticcheck // Kept for compatibility.
+}
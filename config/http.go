@@ -31,10 +31,12 @@ func DefaultCommonDownstreamData() *CommonDownstreamData {
 
 // CommonDownstreamData collects all the client http configuration.
 type CommonDownstreamData struct {
-	ServiceURL      string              `yaml:"serviceURL" mapstructure:"serviceURL"`
-	ClientTransport Transport           `yaml:"clientTransport" mapstructure:"clientTransport"`
-	ClientTimeout   time.Duration       `yaml:"clientTimeout" mapstructure:"clientTimeout" validate:"timeout=1ms:60s"`
-	Headers         map[string][]string `yaml:"headers" mapstructure:"headers"`
+	ServiceURL      string                `yaml:"serviceURL" mapstructure:"serviceURL"`
+	ClientTransport Transport             `yaml:"clientTransport" mapstructure:"clientTransport"`
+	ClientTimeout   time.Duration         `yaml:"clientTimeout" mapstructure:"clientTimeout" validate:"timeout=1ms:60s"`
+	Headers         map[string][]string   `yaml:"headers" mapstructure:"headers"`
+	Retry           *RetryConfig          `yaml:"retry" mapstructure:"retry"`
+	CircuitBreaker  *CircuitBreakerConfig `yaml:"circuitBreaker" mapstructure:"circuitBreaker"`
 }
 
 // Transport is used to initialise DefaultHTTPTransport.
@@ -66,7 +68,12 @@ func (g *CommonDownstreamData) Validate() error {
 			return err
 		}
 	}
-	return nil
+
+	if err := g.Retry.Validate(); err != nil {
+		return err
+	}
+
+	return g.CircuitBreaker.Validate()
 }
 
 type CommonServerConfig struct {
@@ -116,24 +123,31 @@ func proxyHandlerFromConfig(cfg *Transport) func(req *http.Request) (*url.URL, e
 // defaultHTTPTransport returns a new *http.Transport with the same configuration as http.DefaultTransport.
 func defaultHTTPTransport(ctx context.Context, cfg *Transport) (*http.Transport, error) {
 	// Finalise the handler loading
-	tlsConfig, err := MakeTLSConfig(ctx, cfg.ClientTLS)
+	tlsConfig, pool, err := makeTLSConfig(ctx, cfg.ClientTLS)
 	if err != nil {
 		return nil, err
 	}
 
-	return &http.Transport{
-		Proxy: proxyHandlerFromConfig(cfg),
-		DialContext: (&net.Dialer{
-			Timeout:   cfg.Dialer.Timeout,
-			KeepAlive: cfg.Dialer.KeepAlive,
-			DualStack: cfg.Dialer.DualStack,
-		}).DialContext,
+	dialer := &net.Dialer{
+		Timeout:   cfg.Dialer.Timeout,
+		KeepAlive: cfg.Dialer.KeepAlive,
+		DualStack: cfg.Dialer.DualStack,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxyHandlerFromConfig(cfg),
+		DialContext:           dialer.DialContext,
 		MaxIdleConns:          cfg.MaxIdleConns,
 		IdleConnTimeout:       cfg.IdleConnTimeout,
 		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
 		ExpectContinueTimeout: cfg.ExpectContinueTimeout,
 		TLSClientConfig:       tlsConfig,
-	}, nil
+	}
+	if pool != nil {
+		// Trust the reloaded pool. Connections tunnelled through a proxy are still made with
+		// TLSClientConfig, which trusts the pool loaded when the transport was made.
+		transport.DialTLSContext = pool.dialTLSContext(tlsConfig, dialer.DialContext, cfg.TLSHandshakeTimeout)
+	}
+	return transport, nil
 }
 
 // DefaultHTTPClient returns a new *http.Client with sensible defaults, in particular it has a timeout set.
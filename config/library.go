@@ -5,6 +5,7 @@ import (
 
 	"github.com/anz-bank/sysl-go/log"
 
+	"github.com/anz-bank/sysl-go/credauth"
 	"github.com/anz-bank/sysl-go/jwtauth"
 	"github.com/anz-bank/sysl-go/validator"
 )
@@ -15,7 +16,9 @@ type LibraryConfig struct {
 	Profiling      bool                  `yaml:"profiling" mapstructure:"profiling"`
 	Health         bool                  `yaml:"health" mapstructure:"health"`
 	Authentication *AuthenticationConfig `yaml:"authentication" mapstructure:"authentication"`
+	Authorization  *AuthorizationConfig  `yaml:"authorization" mapstructure:"authorization"`
 	Trace          TraceConfig           `yaml:"trace" mapstructure:"trace"`
+	Lifecycle      LifecycleConfig       `yaml:"lifecycle" mapstructure:"lifecycle"`
 }
 
 type AdminConfig struct {
@@ -31,16 +34,54 @@ type LogConfig struct {
 
 	// LogPayload logs the contents of request and response objects.
 	LogPayload bool `yaml:"logPayload" mapstructure:"logPayload"`
+
+	// PayloadRedaction configures the redaction of the payloads logged when LogPayload is set.
+	PayloadRedaction *PayloadRedactionConfig `yaml:"payloadRedaction" mapstructure:"payloadRedaction"`
+
+	// AccessLog configures the log of each completed HTTP request.
+	AccessLog *AccessLogConfig `yaml:"accessLog" mapstructure:"accessLog"`
 }
 
 // AuthenticationConfig struct.
+//
+// Requests to endpoints with authorization rules are authenticated with a JWT bearer token, an API
+// key or HTTP Basic credentials, as configured. The principals of API keys and Basic credentials
+// are described by claims, so the same authorization rules apply to them.
 type AuthenticationConfig struct {
-	JWTAuth *jwtauth.Config `yaml:"jwtauth" mapstructure:"jwtauth"`
+	JWTAuth *jwtauth.Config        `yaml:"jwtauth" mapstructure:"jwtauth"`
+	APIKey  *credauth.APIKeyConfig `yaml:"apiKey" mapstructure:"apiKey"`
+	Basic   *credauth.BasicConfig  `yaml:"basic" mapstructure:"basic"`
 }
 
 // TraceConfig struct.
 type TraceConfig struct {
 	IncomingHeaderForID string `yaml:"incomingHeaderForID" mapstructure:"incomingHeaderForID"`
+	// OutgoingHeaderForID is the header (or gRPC metadata key) the trace ID is written to on
+	// downstream requests. Defaults to IncomingHeaderForID.
+	OutgoingHeaderForID string `yaml:"outgoingHeaderForID" mapstructure:"outgoingHeaderForID"`
+	// Propagators are the formats in which trace contexts are read from inbound requests and written
+	// to downstream requests: "tracecontext" (W3C traceparent and tracestate) and/or "b3". Inbound
+	// requests are read with the first format found. Defaults to both.
+	Propagators []string `yaml:"propagators" mapstructure:"propagators"`
+}
+
+// LifecycleConfig struct.
+type LifecycleConfig struct {
+	// HandleSignals directs the server to trap SIGINT and SIGTERM and gracefully stop itself
+	// rather than leaving signal handling to the application.
+	HandleSignals bool `yaml:"handleSignals" mapstructure:"handleSignals"`
+
+	// DrainPeriod is how long to wait, after the health server has been marked not-ready,
+	// before the servers are told to stop. This gives load balancers time to stop routing
+	// new requests to the instance.
+	DrainPeriod time.Duration `yaml:"drainPeriod" mapstructure:"drainPeriod"`
+
+	// GracefulStopTimeout bounds the overall time allowed for all servers to gracefully stop.
+	// Servers still running after this time are stopped un-gracefully.
+	GracefulStopTimeout time.Duration `yaml:"gracefulStopTimeout" mapstructure:"gracefulStopTimeout"`
+
+	// ShutdownHookTimeout bounds the context given to the Hooks.OnShutdown functions.
+	ShutdownHookTimeout time.Duration `yaml:"shutdownHookTimeout" mapstructure:"shutdownHookTimeout"`
 }
 
 func (c *LibraryConfig) Validate() error {
@@ -48,6 +89,15 @@ func (c *LibraryConfig) Validate() error {
 	if err := validator.Validate(c); err != nil {
 		return err
 	}
+	if err := c.Authorization.Validate(); err != nil {
+		return err
+	}
+	if err := c.Log.AccessLog.Validate(); err != nil {
+		return err
+	}
+	if err := c.Log.PayloadRedaction.Validate(); err != nil {
+		return err
+	}
 
 	return nil
 }
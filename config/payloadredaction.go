@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// PayloadRedactionConfig configures the redaction of request and response payloads logged when
+// LogPayload is set, for both the requests the server receives and those it makes downstream.
+type PayloadRedactionConfig struct {
+	// Headers are the names of the headers whose values are redacted, in addition to the
+	// Authorization, Proxy-Authorization, Cookie, Set-Cookie and X-API-Key headers and the header
+	// of API keys configured for authentication.
+	Headers []string `yaml:"headers" mapstructure:"headers"`
+
+	// QueryParams are the names of the query parameters whose values are redacted from the logged
+	// URLs of requests, in addition to the query parameter of API keys configured for
+	// authentication. Logged URLs are redacted whether or not LogPayload is set.
+	QueryParams []string `yaml:"queryParams" mapstructure:"queryParams"`
+
+	// BodyFields are the paths of the fields of JSON bodies whose values are masked, e.g.
+	// "customer.email". A "*" segment matches every field of an object or element of an array,
+	// e.g. "cards.*.number". Bodies that are not JSON are not logged when fields are configured.
+	BodyFields []string `yaml:"bodyFields" mapstructure:"bodyFields"`
+
+	// MaxBodySize is the number of bytes of a body logged before the rest is truncated.
+	// Unset (or 0) logs the whole body.
+	MaxBodySize int `yaml:"maxBodySize" mapstructure:"maxBodySize"`
+
+	// ContentTypes are the media types of the bodies that are logged, e.g. "application/json" or
+	// "text/*". Bodies of other media types are omitted. Unset logs bodies of every media type.
+	ContentTypes []string `yaml:"contentTypes" mapstructure:"contentTypes"`
+}
+
+func (c *PayloadRedactionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, field := range c.BodyFields {
+		for _, segment := range strings.Split(strings.TrimPrefix(field, "$."), ".") {
+			if segment == "" {
+				return fmt.Errorf("log.payloadRedaction.bodyFields: invalid path %s", field)
+			}
+		}
+	}
+	if c.MaxBodySize < 0 {
+		return errors.New("log.payloadRedaction.maxBodySize must not be negative")
+	}
+	for _, contentType := range c.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			return fmt.Errorf("log.payloadRedaction.contentTypes: invalid media type %s", contentType)
+		}
+	}
+	return nil
+}
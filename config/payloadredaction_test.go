@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadRedactionConfigValidate(t *testing.T) {
+	var nilCfg *PayloadRedactionConfig
+	require.NoError(t, nilCfg.Validate())
+
+	cfg := &PayloadRedactionConfig{
+		BodyFields:   []string{"$.customer.email", "cards.*.number"},
+		MaxBodySize:  1024,
+		ContentTypes: []string{"application/json", "text/*"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.BodyFields = []string{"customer..email"}
+	require.EqualError(t, cfg.Validate(), "log.payloadRedaction.bodyFields: invalid path customer..email")
+
+	cfg.BodyFields = nil
+	cfg.MaxBodySize = -1
+	require.EqualError(t, cfg.Validate(), "log.payloadRedaction.maxBodySize must not be negative")
+
+	cfg.MaxBodySize = 0
+	cfg.ContentTypes = []string{"application/"}
+	require.EqualError(t, cfg.Validate(), "log.payloadRedaction.contentTypes: invalid media type application/")
+
+	lib := defaultConfig()
+	lib.Log.PayloadRedaction = cfg
+	require.Error(t, lib.Validate())
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Defaults applied to a RetryConfig by WithDefaults.
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = 100 * time.Millisecond
+	DefaultRetryMaxBackoff     = 2 * time.Second
+	DefaultRetryMultiplier     = 2.0
+	DefaultRetryJitter         = 0.2
+)
+
+// DefaultRetryableStatusCodes are the response status codes retried when none are configured.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryConfig configures the retry of failed requests to a downstream HTTP service. Requests are
+// retried when they fail with an error or with one of the retryable status codes, waiting between
+// attempts for an exponentially increasing backoff (or the duration given by a Retry-After response
+// header, capped by MaxBackoff). Retries never extend beyond the deadline of the request context.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts made, including the first.
+	MaxAttempts int `yaml:"maxAttempts" mapstructure:"maxAttempts"`
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration `yaml:"initialBackoff" mapstructure:"initialBackoff"`
+	// MaxBackoff caps the wait between attempts, including waits given by Retry-After headers.
+	MaxBackoff time.Duration `yaml:"maxBackoff" mapstructure:"maxBackoff"`
+	// Multiplier is the factor the backoff grows by after each retry.
+	Multiplier float64 `yaml:"multiplier" mapstructure:"multiplier"`
+	// Jitter is the fraction (between 0 and 1) of each backoff that is randomised. Set it to 0 to
+	// disable jitter.
+	Jitter *float64 `yaml:"jitter" mapstructure:"jitter"`
+	// RetryableStatusCodes are the response status codes that are retried.
+	RetryableStatusCodes []int `yaml:"retryableStatusCodes" mapstructure:"retryableStatusCodes"`
+	// RetryNonIdempotent enables the retry of requests with non-idempotent methods (e.g. POST).
+	RetryNonIdempotent bool `yaml:"retryNonIdempotent" mapstructure:"retryNonIdempotent"`
+	// IgnoreRetryAfter disables the use of the Retry-After response header as the backoff.
+	IgnoreRetryAfter bool `yaml:"ignoreRetryAfter" mapstructure:"ignoreRetryAfter"`
+}
+
+// WithDefaults returns a copy of the config with defaults set against any unset values.
+func (r RetryConfig) WithDefaults() RetryConfig {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if r.InitialBackoff == 0 {
+		r.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if r.MaxBackoff == 0 {
+		r.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if r.Multiplier == 0 {
+		r.Multiplier = DefaultRetryMultiplier
+	}
+	if r.Jitter == nil {
+		jitter := DefaultRetryJitter
+		r.Jitter = &jitter
+	}
+	if len(r.RetryableStatusCodes) == 0 {
+		r.RetryableStatusCodes = DefaultRetryableStatusCodes
+	}
+	return r
+}
+
+func (r *RetryConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("retry.maxAttempts must not be negative")
+	}
+	if r.InitialBackoff < 0 || r.MaxBackoff < 0 {
+		return fmt.Errorf("retry backoff must not be negative")
+	}
+	if r.Multiplier != 0 && r.Multiplier < 1 {
+		return fmt.Errorf("retry.multiplier must be at least 1")
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		return fmt.Errorf("retry.jitter must be between 0 and 1")
+	}
+	return nil
+}
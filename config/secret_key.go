@@ -3,12 +3,16 @@ package config
 import (
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"sort"
 	"strings"
+
+	pkcs12 "github.com/anz-bank/go-pkcs12"
 )
 
 const (
 	SecretKeyEncodingBase64 = "base64"
+	SecretKeyEncodingPKCS12 = "pkcs12"
 )
 
 type SecretKeyConfig struct {
@@ -21,6 +25,7 @@ type SecretKeyConfig struct {
 
 var SecretKeyValidators = map[string]func(cfg *SecretKeyConfig) error{
 	SecretKeyEncodingBase64: validateBase64Value,
+	SecretKeyEncodingPKCS12: validatePKCS12KeyStore,
 }
 
 func (s *SecretKeyConfig) Validate() error {
@@ -61,6 +66,7 @@ type SecretKey struct {
 
 var SecretKeyReader = map[string]func(cfg *SecretKeyConfig) ([]byte, error){
 	SecretKeyEncodingBase64: readBase64Value,
+	SecretKeyEncodingPKCS12: readPKCS12KeyStore,
 }
 
 func MakeSecretKey(cfg *SecretKeyConfig) (*SecretKey, error) {
@@ -93,3 +99,40 @@ func MakeSecretKey(cfg *SecretKeyConfig) (*SecretKey, error) {
 func readBase64Value(cfg *SecretKeyConfig) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(cfg.Value.Value())
 }
+
+func validatePKCS12KeyStore(cfg *SecretKeyConfig) error {
+	if cfg.KeyStore == nil || *cfg.KeyStore == "" {
+		return fmt.Errorf("keyStore config missing")
+	}
+	return nil
+}
+
+// readPKCS12KeyStore reads the secret key held against the configured alias in the PKCS#12
+// keystore. The alias may be omitted when the keystore holds a single secret key.
+func readPKCS12KeyStore(cfg *SecretKeyConfig) ([]byte, error) {
+	p12bytes, err := ioutil.ReadFile(*cfg.KeyStore)
+	if err != nil {
+		return nil, fmt.Errorf("secret key load error, unable to read keyStore: %w", err)
+	}
+	var pass string
+	if cfg.KeyStorePassword != nil {
+		pass = cfg.KeyStorePassword.Value()
+	}
+	_, _, secretKeys, err := pkcs12.DecodeAll(p12bytes, pass)
+	if err != nil {
+		return nil, fmt.Errorf("secret key load error, unable to decode keyStore: %w", err)
+	}
+
+	if cfg.Alias == nil || *cfg.Alias == "" {
+		if len(secretKeys) != 1 {
+			return nil, fmt.Errorf("secret key load error, alias config missing and keyStore holds %d secret keys", len(secretKeys))
+		}
+		return secretKeys[0].Key(), nil
+	}
+	for _, key := range secretKeys {
+		if key.FriendlyName() == *cfg.Alias {
+			return key.Key(), nil
+		}
+	}
+	return nil, fmt.Errorf("secret key load error, alias `%s` not found in keyStore", *cfg.Alias)
+}
@@ -31,7 +31,7 @@ func TestSecretKeyConfigValidation(t *testing.T) {
 			&SecretKeyConfig{
 				Encoding: NewString("abcdefg"),
 			},
-			fmt.Errorf("encoding `abcdefg` is invalid, must be one of [\"base64\"]"),
+			fmt.Errorf("encoding `abcdefg` is invalid, must be one of [\"base64\" \"pkcs12\"]"),
 		},
 		{
 			"secretKey.value config missing",
@@ -96,3 +96,72 @@ func TestMakeSecretKeyInvalidConfig(t *testing.T) {
 	assert.Nil(t, secretKey)
 	assert.EqualError(t, err, "encoding config missing")
 }
+
+func TestMakeSecretKeyPKCS12Success(t *testing.T) {
+	cfg := &SecretKeyConfig{
+		Encoding:         NewString("pkcs12"),
+		KeyStore:         NewString("testdata/secretkeys.p12"),
+		KeyStorePassword: NewSecret("changeit"),
+		Alias:            NewString("second"),
+	}
+
+	// When
+	secretKey, err := MakeSecretKey(cfg)
+
+	// Then
+	assert.NoError(t, err)
+	assert.NotNil(t, secretKey)
+	assert.Equal(t, "fedcba9876543210", secretKey.Value())
+}
+
+func TestMakeSecretKeyPKCS12Errors(t *testing.T) {
+	testData := []struct {
+		name string
+		in   *SecretKeyConfig
+		out  string
+	}{
+		{
+			"keyStore config missing",
+			&SecretKeyConfig{Encoding: NewString("pkcs12")},
+			"keyStore config missing",
+		},
+		{
+			"alias not found",
+			&SecretKeyConfig{
+				Encoding:         NewString("pkcs12"),
+				KeyStore:         NewString("testdata/secretkeys.p12"),
+				KeyStorePassword: NewSecret("changeit"),
+				Alias:            NewString("third"),
+			},
+			"secret key load error, alias `third` not found in keyStore",
+		},
+		{
+			"alias missing with multiple keys",
+			&SecretKeyConfig{
+				Encoding:         NewString("pkcs12"),
+				KeyStore:         NewString("testdata/secretkeys.p12"),
+				KeyStorePassword: NewSecret("changeit"),
+			},
+			"secret key load error, alias config missing and keyStore holds 2 secret keys",
+		},
+		{
+			"wrong password",
+			&SecretKeyConfig{
+				Encoding:         NewString("pkcs12"),
+				KeyStore:         NewString("testdata/secretkeys.p12"),
+				KeyStorePassword: NewSecret("wrong"),
+				Alias:            NewString("first"),
+			},
+			"secret key load error, unable to decode keyStore: pkcs12: decryption password incorrect",
+		},
+	}
+
+	for _, tt := range testData {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			secretKey, err := MakeSecretKey(tt.in)
+			assert.Nil(t, secretKey)
+			assert.EqualError(t, err, tt.out)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	SecretSchemeFile = "file"
+	SecretSchemeEnv  = "env"
+)
+
+// SecretPrefix marks a SensitiveString configuration value as a reference to a secret, e.g.
+// "secret://env:DB_PASS". Values without the prefix are used literally.
+const SecretPrefix = "secret://"
+
+// SecretResolver resolves a reference to a secret into the value of the secret.
+type SecretResolver interface {
+	// ResolveSecret returns the value of the secret identified by the given reference, which
+	// includes the scheme the resolver is registered against but not the SecretPrefix (e.g.
+	// "env:DB_PASS" for "secret://env:DB_PASS").
+	ResolveSecret(ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function into a SecretResolver.
+type SecretResolverFunc func(ref string) (string, error)
+
+// ResolveSecret calls f(ref).
+func (f SecretResolverFunc) ResolveSecret(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		SecretSchemeFile: SecretResolverFunc(resolveFileSecret),
+		SecretSchemeEnv:  SecretResolverFunc(resolveEnvSecret),
+	}
+)
+
+// RegisterSecretResolver registers the resolver used to resolve references to secrets with the
+// given scheme, replacing any resolver already registered against the scheme. Resolvers must be
+// registered before the configuration is loaded.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[strings.ToLower(scheme)] = resolver
+}
+
+// ResolveSecret returns the value of the secret referenced by the given value when the value is of
+// the form secret://<scheme>:<reference> (see SecretPrefix and RegisterSecretResolver). Any other
+// value is returned unchanged.
+func ResolveSecret(value string) (string, error) {
+	if !strings.HasPrefix(value, SecretPrefix) {
+		return value, nil
+	}
+	ref := strings.TrimPrefix(value, SecretPrefix)
+	i := strings.Index(ref, ":")
+	if i <= 0 {
+		return "", fmt.Errorf("unable to resolve secret `%s`: scheme missing", value)
+	}
+	secretResolversMu.RLock()
+	resolver, ok := secretResolvers[strings.ToLower(ref[:i])]
+	secretResolversMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unable to resolve secret `%s`: unknown scheme %s", value, ref[:i])
+	}
+	secret, err := resolver.ResolveSecret(ref)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret `%s`: %w", value, err)
+	}
+	return secret, nil
+}
+
+// resolveFileSecret reads the secret from the file referenced by file:///path/to/file. Trailing line
+// endings are removed from the content of the file.
+func resolveFileSecret(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	path := u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+	if path == "" {
+		return "", fmt.Errorf("file path missing")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// resolveEnvSecret reads the secret from the environment variable referenced by env:NAME.
+func resolveEnvSecret(ref string) (string, error) {
+	name := ref[strings.Index(ref, ":")+1:]
+	if name == "" {
+		return "", fmt.Errorf("environment variable name missing")
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecretLiteral(t *testing.T) {
+	for _, value := range []string{"", "plain", ":leading", "unknown:scheme", "env:NOT_A_REFERENCE", "file:///not/a/reference"} {
+		resolved, err := ResolveSecret(value)
+		require.NoError(t, err)
+		require.Equal(t, value, resolved)
+	}
+}
+
+func TestResolveSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, ioutil.WriteFile(path, []byte("s3cret\n"), 0600))
+
+	resolved, err := ResolveSecret("secret://file://" + path)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", resolved)
+
+	_, err = ResolveSecret("secret://file:///does/not/exist")
+	require.EqualError(t, err, "unable to resolve secret `secret://file:///does/not/exist`: open /does/not/exist: no such file or directory")
+}
+
+func TestResolveSecretEnv(t *testing.T) {
+	t.Setenv("SYSLGO_TEST_SECRET", "s3cret")
+
+	resolved, err := ResolveSecret("secret://env:SYSLGO_TEST_SECRET")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", resolved)
+
+	_, err = ResolveSecret("secret://env:SYSLGO_TEST_SECRET_UNSET")
+	require.EqualError(t, err, "unable to resolve secret `secret://env:SYSLGO_TEST_SECRET_UNSET`: environment variable SYSLGO_TEST_SECRET_UNSET is not set")
+}
+
+func TestResolveSecretInvalidReference(t *testing.T) {
+	_, err := ResolveSecret("secret://unknown:ref")
+	require.EqualError(t, err, "unable to resolve secret `secret://unknown:ref`: unknown scheme unknown")
+
+	_, err = ResolveSecret("secret://ref")
+	require.EqualError(t, err, "unable to resolve secret `secret://ref`: scheme missing")
+}
+
+func TestResolveSecretCustomScheme(t *testing.T) {
+	RegisterSecretResolver("vault", SecretResolverFunc(func(ref string) (string, error) {
+		if ref == "vault:secret/db#password" {
+			return "from-vault", nil
+		}
+		return "", fmt.Errorf("not found")
+	}))
+	defer func() {
+		secretResolversMu.Lock()
+		defer secretResolversMu.Unlock()
+		delete(secretResolvers, "vault")
+	}()
+
+	resolved, err := ResolveSecret("secret://vault:secret/db#password")
+	require.NoError(t, err)
+	require.Equal(t, "from-vault", resolved)
+}
+
+func TestUnmarshalResolvesSensitiveString(t *testing.T) {
+	type DemoConfig struct {
+		Password SensitiveString `mapstructure:"password"`
+	}
+
+	t.Setenv("SYSLGO_TEST_DB_PASS", "s3cret")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "a.yaml", []byte("password: secret://env:SYSLGO_TEST_DB_PASS"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "b.yaml", []byte("password: env:SYSLGO_TEST_DB_PASS"), 0644))
+
+	conf := DemoConfig{}
+	require.NoError(t, NewConfigReaderBuilder().WithFs(fs).WithConfigFile("a.yaml").Build().Unmarshal(&conf))
+	require.Equal(t, "s3cret", conf.Password.Value())
+
+	// Values without the secret prefix are used literally.
+	conf = DemoConfig{}
+	require.NoError(t, NewConfigReaderBuilder().WithFs(fs).WithConfigFile("b.yaml").Build().Unmarshal(&conf))
+	require.Equal(t, "env:SYSLGO_TEST_DB_PASS", conf.Password.Value())
+}
@@ -0,0 +1,59 @@
+// Package sensitive provides a string type that hides its value when printed or marshalled.
+//
+// It is kept apart from the config package so that packages the config package depends on (such
+// as jwtauth) can hold sensitive values too. Use it through config.SensitiveString.
+package sensitive
+
+import (
+	"encoding/json"
+)
+
+const DefaultReplacementText = "****************"
+
+type String struct {
+	s           string
+	replacement *string
+}
+
+func NewString(from string) String {
+	r := DefaultReplacementText
+	return String{from, &r}
+}
+
+func (s String) String() string {
+	if s.replacement == nil {
+		r := DefaultReplacementText
+		s.replacement = &r
+	}
+	return *s.replacement
+}
+func (s *String) Value() string {
+	return s.s
+}
+
+func (s *String) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var val string
+	if err := unmarshal(&val); err != nil {
+		return err
+	}
+	s.s = val
+	return nil
+}
+
+// Note, this one needs to be an object receiver NOT a pointer receiver.
+func (s String) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+func (s *String) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	s.s = val
+	return nil
+}
+
+func (s *String) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	pkcs12 "github.com/anz-bank/go-pkcs12"
 	"github.com/anz-bank/sysl-go/log"
@@ -85,12 +86,19 @@ type TrustedCertPoolConfig struct {
 	Encoding *string          `yaml:"encoding" mapstructure:"encoding"`
 	Path     *string          `yaml:"path" mapstructure:"path"`
 	Password *SensitiveString `yaml:"password" mapstructure:"password"`
+	// ReloadInterval, when set, is the minimum interval between checks of the files of the pool for
+	// changes. Changed files are reloaded without restarting, affecting new connections only. See
+	// MakeTLSConfig for the clients that trust the reloaded pool.
+	ReloadInterval time.Duration `yaml:"reloadInterval" mapstructure:"reloadInterval"`
 }
 
 type ServerIdentityConfig struct {
 	CertKeyPair *CertKeyPair `yaml:"certKeyPair" mapstructure:"certKeyPair"`
 	// Add Pkcs12Store to store cert and key as it is protected by password
 	PKCS12Store *Pkcs12Store `yaml:"p12Store" mapstructure:"p12Store"`
+	// ReloadInterval, when set, is the minimum interval between checks of the files of the identity
+	// for changes. Changed files are reloaded without restarting, affecting new connections only.
+	ReloadInterval time.Duration `yaml:"reloadInterval" mapstructure:"reloadInterval"`
 }
 
 type CertKeyPair struct {
@@ -193,48 +201,68 @@ func OurIdentityCertificates(cfg *TLSConfig) ([]tls.Certificate, error) {
 
 	cs := make([]tls.Certificate, 0)
 	for _, identity := range cfg.ServerIdentities {
-		if identity == nil {
+		if identity == nil || (identity.CertKeyPair == nil && identity.PKCS12Store == nil) {
 			continue
 		}
 
-		if identity.CertKeyPair != nil {
-			pair := identity.CertKeyPair
-			cert, err := tls.LoadX509KeyPair(*pair.CertPath, *pair.KeyPath)
-			if err != nil {
-				return nil, err
-			}
-			cs = append(cs, cert)
-		} else if identity.PKCS12Store != nil {
-			passBytes, err := base64.StdEncoding.DecodeString((identity.PKCS12Store.Password).Value())
-			if err != nil {
-				return nil, err
-			}
+		cert, err := loadIdentityCertificate(identity)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, cert)
+	}
 
-			pass := string(passBytes)
+	return cs, nil
+}
 
-			tlsCert := tls.Certificate{}
+func loadIdentityCertificate(identity *ServerIdentityConfig) (tls.Certificate, error) {
+	if identity.CertKeyPair != nil {
+		pair := identity.CertKeyPair
+		return tls.LoadX509KeyPair(*pair.CertPath, *pair.KeyPath)
+	}
 
-			p12bytes, err := ioutil.ReadFile(*identity.PKCS12Store.Path)
-			if err != nil {
-				return nil, err
-			}
+	if identity.PKCS12Store == nil {
+		return tls.Certificate{}, fmt.Errorf("p12Store/certKeyPair: one must be configured")
+	}
 
-			privKey, cert, caCerts, err := pkcs12.DecodeChain(p12bytes, pass)
-			if err != nil {
-				return nil, err
-			}
+	passBytes, err := base64.StdEncoding.DecodeString((identity.PKCS12Store.Password).Value())
+	if err != nil {
+		return tls.Certificate{}, err
+	}
 
-			tlsCert.PrivateKey = privKey
-			tlsCert.Certificate = append(tlsCert.Certificate, cert.Raw)
-			tlsCert.Leaf = cert
-			for _, caCert := range caCerts {
-				tlsCert.Certificate = append(tlsCert.Certificate, caCert.Raw)
-			}
-			cs = append(cs, tlsCert)
-		}
+	pass := string(passBytes)
+
+	tlsCert := tls.Certificate{}
+
+	p12bytes, err := ioutil.ReadFile(*identity.PKCS12Store.Path)
+	if err != nil {
+		return tls.Certificate{}, err
 	}
 
-	return cs, nil
+	privKey, cert, caCerts, err := pkcs12.DecodeChain(p12bytes, pass)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	tlsCert.PrivateKey = privKey
+	tlsCert.Certificate = append(tlsCert.Certificate, cert.Raw)
+	tlsCert.Leaf = cert
+	for _, caCert := range caCerts {
+		tlsCert.Certificate = append(tlsCert.Certificate, caCert.Raw)
+	}
+	return tlsCert, nil
+}
+
+// files returns the files the identity is loaded from.
+func (b *ServerIdentityConfig) files() ([]string, error) {
+	switch {
+	case b.CertKeyPair != nil:
+		return []string{*b.CertKeyPair.CertPath, *b.CertKeyPair.KeyPath}, nil
+	case b.PKCS12Store != nil:
+		return []string{*b.PKCS12Store.Path}, nil
+	default:
+		return nil, fmt.Errorf("p12Store/certKeyPair: one must be configured")
+	}
 }
 
 func findCertsFromPath(cfg *TrustedCertPoolConfig) ([]string, error) {
@@ -404,50 +432,64 @@ func makeSelfSignedTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
 	}, nil
 }
 
-//nolint:funlen
+// MakeTLSConfig returns the TLS settings for the given config. If the trusted cert pool is reloaded
+// from disk (see TrustedCertPoolConfig.ReloadInterval), servers using the settings verify client
+// certificates against the reloaded pool, whereas clients using the settings trust the pool loaded
+// when the settings were made. The clients returned by DefaultHTTPClient and configured by
+// DefaultGrpcDialOptions trust the reloaded pool.
 func MakeTLSConfig(ctx context.Context, cfg *TLSConfig) (*tls.Config, error) {
+	settings, _, err := makeTLSConfig(ctx, cfg)
+	return settings, err
+}
+
+// makeTLSConfig returns the TLS settings for the given config, along with the reloader of the
+// trusted cert pool if it is reloaded from disk.
+//
+//nolint:funlen
+func makeTLSConfig(ctx context.Context, cfg *TLSConfig) (*tls.Config, *poolReloader, error) {
 	if cfg == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	if cfg.InsecureSkipVerify {
 		//nolint:gosec // This is configured by the user
 		log.Info(ctx, "It is insecure due to skipping server certificate verification")
-		return &tls.Config{InsecureSkipVerify: true}, nil
+		return &tls.Config{InsecureSkipVerify: true}, nil, nil
 	}
 
 	if cfg.SelfSigned {
-		return makeSelfSignedTLSConfig(cfg)
+		settings, err := makeSelfSignedTLSConfig(cfg)
+		return settings, nil, err
 	}
 
 	trustedCAs, err := GetTrustedCAs(ctx, cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	tlsMin, tlsMax, err := TLSVersions(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ciphers, err := TLSCiphers(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	policy, err := TLSClientAuth(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ourIdentityCertificates, err := OurIdentityCertificates(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	renegotiation, err := TLSRenegotiationSupport(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	settings := &tls.Config{
@@ -474,7 +516,12 @@ func MakeTLSConfig(ctx context.Context, cfg *TLSConfig) (*tls.Config, error) {
 		Renegotiation: *renegotiation,
 	}
 
-	return settings, nil
+	pool, err := makeReloadingTLSConfig(ctx, cfg, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return settings, pool, nil
 }
 
 //nolint:funlen // TODO: Break this into smaller functions
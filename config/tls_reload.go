@@ -0,0 +1,324 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of reloaded TLS material, as reported in the reload metrics.
+const (
+	tlsReloadKindIdentity        = "serverIdentity"
+	tlsReloadKindTrustedCertPool = "trustedCertPool"
+)
+
+var (
+	tlsReloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tls_certificate_reloads_total",
+			Help: "TLS certificate reloads from disk, by kind and result",
+		},
+		[]string{"kind", "result"},
+	)
+	tlsReloadLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "tls_certificate_reload_last_success_timestamp_seconds",
+			Help: "Time of the last successful TLS certificate reload from disk, by kind",
+		},
+		[]string{"kind"},
+	)
+)
+
+// TLSReloadCollectors returns the collectors of the TLS certificate reload metrics, for registration
+// against a prometheus registry.
+func TLSReloadCollectors() []prometheus.Collector {
+	return []prometheus.Collector{tlsReloads, tlsReloadLastSuccess}
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// reloader polls a set of files, at most once per interval, and calls load when any of them change.
+// Polling happens on demand (i.e. during TLS handshakes) rather than in the background.
+type reloader struct {
+	ctx      context.Context
+	kind     string
+	interval time.Duration
+	files    func() ([]string, error)
+	load     func() error
+
+	m      sync.Mutex
+	next   time.Time
+	stamps map[string]fileStamp
+}
+
+func newReloader(ctx context.Context, kind string, interval time.Duration, files func() ([]string, error), load func() error) (*reloader, error) {
+	r := &reloader{ctx: ctx, kind: kind, interval: interval, files: files, load: load}
+	stamps, err := r.stampFiles()
+	if err != nil {
+		return nil, err
+	}
+	r.stamps = stamps
+	r.next = time.Now().Add(interval)
+	return r, nil
+}
+
+func (r *reloader) stampFiles() (map[string]fileStamp, error) {
+	files, err := r.files()
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// maybeReload reloads when the interval has elapsed since the last poll and the files have changed.
+// On failure the previously loaded material is kept and the reload is retried on the next poll.
+func (r *reloader) maybeReload() {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	now := time.Now()
+	if now.Before(r.next) {
+		return
+	}
+	r.next = now.Add(r.interval)
+
+	stamps, err := r.stampFiles()
+	if err == nil {
+		if reflect.DeepEqual(stamps, r.stamps) {
+			return
+		}
+		err = r.load()
+	}
+	if err != nil {
+		log.Error(r.ctx, err, fmt.Sprintf("failed to reload %s from disk", r.kind))
+		tlsReloads.WithLabelValues(r.kind, "failure").Inc()
+		return
+	}
+
+	r.stamps = stamps
+	log.Infof(r.ctx, "reloaded %s from disk", r.kind)
+	tlsReloads.WithLabelValues(r.kind, "success").Inc()
+	tlsReloadLastSuccess.WithLabelValues(r.kind).Set(float64(now.Unix()))
+}
+
+// identityReloader holds the identity certificates, reloading those configured with a reload interval.
+type identityReloader struct {
+	m         sync.RWMutex
+	certs     []tls.Certificate
+	reloaders []*reloader
+}
+
+func newIdentityReloader(ctx context.Context, identities []*ServerIdentityConfig) (*identityReloader, error) {
+	ir := &identityReloader{}
+	for _, identity := range identities {
+		if identity == nil || (identity.CertKeyPair == nil && identity.PKCS12Store == nil) {
+			continue
+		}
+		identity := identity
+		i := len(ir.certs)
+		cert, err := loadIdentityCertificate(identity)
+		if err != nil {
+			return nil, err
+		}
+		ir.certs = append(ir.certs, cert)
+
+		if identity.ReloadInterval <= 0 {
+			continue
+		}
+		r, err := newReloader(ctx, tlsReloadKindIdentity, identity.ReloadInterval, identity.files, func() error {
+			cert, err := loadIdentityCertificate(identity)
+			if err != nil {
+				return err
+			}
+			ir.m.Lock()
+			defer ir.m.Unlock()
+			ir.certs[i] = cert
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		ir.reloaders = append(ir.reloaders, r)
+	}
+	return ir, nil
+}
+
+func (ir *identityReloader) certificates() []tls.Certificate {
+	for _, r := range ir.reloaders {
+		r.maybeReload()
+	}
+	ir.m.RLock()
+	defer ir.m.RUnlock()
+	return append([]tls.Certificate{}, ir.certs...)
+}
+
+// getCertificate returns the first identity certificate supported by the client.
+func (ir *identityReloader) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certs := ir.certificates()
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no server identity certificates")
+	}
+	for i := range certs {
+		if hello.SupportsCertificate(&certs[i]) == nil {
+			return &certs[i], nil
+		}
+	}
+	return &certs[0], nil
+}
+
+// getClientCertificate returns the first identity certificate supported by the server, or no
+// certificate if there is none.
+func (ir *identityReloader) getClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certs := ir.certificates()
+	for i := range certs {
+		if info.SupportsCertificate(&certs[i]) == nil {
+			return &certs[i], nil
+		}
+	}
+	return &tls.Certificate{}, nil
+}
+
+// poolReloader holds the trusted cert pool, reloading it as configured.
+type poolReloader struct {
+	m        sync.RWMutex
+	current  *x509.CertPool
+	reloader *reloader
+}
+
+func newPoolReloader(ctx context.Context, cfg *TrustedCertPoolConfig, pool *x509.CertPool) (*poolReloader, error) {
+	pr := &poolReloader{current: pool}
+	r, err := newReloader(ctx, tlsReloadKindTrustedCertPool, cfg.ReloadInterval, func() ([]string, error) {
+		return findCertsFromPath(cfg)
+	}, func() error {
+		pool, err := buildPool(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		pr.m.Lock()
+		defer pr.m.Unlock()
+		pr.current = pool
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	pr.reloader = r
+	return pr, nil
+}
+
+func (pr *poolReloader) pool() *x509.CertPool {
+	pr.reloader.maybeReload()
+	pr.m.RLock()
+	defer pr.m.RUnlock()
+	return pr.current
+}
+
+// clientConfig returns a copy of the given client settings that trusts the current pool. Servers
+// are verified by the standard verification against the host being dialled, which callbacks such
+// as VerifyPeerCertificate cannot see.
+func (pr *poolReloader) clientConfig(settings *tls.Config) *tls.Config {
+	c := settings.Clone()
+	c.RootCAs = pr.pool()
+	return c
+}
+
+// dialTLSContext returns a function for http.Transport.DialTLSContext that establishes connections
+// with the given dial function and then performs the TLS handshake with a copy of the given
+// settings that trusts the current pool.
+func (pr *poolReloader) dialTLSContext(settings *tls.Config, dial func(ctx context.Context, network, addr string) (net.Conn, error), handshakeTimeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		c := pr.clientConfig(settings)
+		if c.ServerName == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			c.ServerName = host
+		}
+		deadline, _ := ctx.Deadline()
+		if handshakeTimeout > 0 && (deadline.IsZero() || time.Now().Add(handshakeTimeout).Before(deadline)) {
+			deadline = time.Now().Add(handshakeTimeout)
+		}
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, c)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
+
+// makeReloadingTLSConfig replaces the static certificates and cert pools of the given settings with
+// callbacks that pick up new certificates from disk as configured, without affecting established
+// connections. If the trusted cert pool is reloaded, its reloader is returned so that clients can
+// trust the current pool (see poolReloader.clientConfig), as the settings only use the reloaded
+// pool when acting as a server.
+func makeReloadingTLSConfig(ctx context.Context, cfg *TLSConfig, settings *tls.Config) (*poolReloader, error) {
+	reloadIdentities := false
+	for _, identity := range cfg.ServerIdentities {
+		if identity != nil && identity.ReloadInterval > 0 {
+			reloadIdentities = true
+		}
+	}
+	if reloadIdentities {
+		ir, err := newIdentityReloader(ctx, cfg.ServerIdentities)
+		if err != nil {
+			return nil, err
+		}
+		settings.Certificates = nil
+		settings.GetCertificate = ir.getCertificate
+		settings.GetClientCertificate = ir.getClientCertificate
+	}
+
+	if cfg.TrustedCertPool == nil || cfg.TrustedCertPool.ReloadInterval <= 0 || *cfg.TrustedCertPool.Mode == SYSMODE {
+		return nil, nil
+	}
+	pr, err := newPoolReloader(ctx, cfg.TrustedCertPool, settings.RootCAs)
+	if err != nil {
+		return nil, err
+	}
+	// Acting as server, each handshake uses the current pool for client certificates. The
+	// handshakes are configured by clones of the settings, so servers must set the NextProtos of the
+	// settings themselves rather than of their own clones.
+	base := settings
+	settings.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.ClientCAs = pr.pool()
+		c.GetConfigForClient = nil
+		return c, nil
+	}
+	return pr, nil
+}
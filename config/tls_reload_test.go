@@ -0,0 +1,237 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// touch moves the modification time of the file forward so that reloads notice the change.
+func touch(t *testing.T, file string, at time.Time) {
+	require.NoError(t, os.Chtimes(file, at, at))
+}
+
+func leafOrganisation(t *testing.T, cert *tls.Certificate) string {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.Organization[0]
+}
+
+func TestTLSConfigReloadsServerIdentity(t *testing.T) {
+	dir := t.TempDir()
+	certFilename := filepath.Join(dir, "cert.pem")
+	keyFilename := filepath.Join(dir, "key.pem")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "first", certFilename, keyFilename))
+
+	identity := &ServerIdentityConfig{
+		CertKeyPair:    &CertKeyPair{CertPath: &certFilename, KeyPath: &keyFilename},
+		ReloadInterval: time.Millisecond,
+	}
+	cfg := NewTLSConfig("1.2", "1.2", "NoClientCert", nil, []*ServerIdentityConfig{identity})
+
+	tlsCfg, err := MakeTLSConfig(ctx, cfg)
+	require.NoError(t, err)
+	require.Nil(t, tlsCfg.Certificates)
+
+	cert, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "first", leafOrganisation(t, cert))
+
+	// Rotate the certificate.
+	successes := promtestutil.ToFloat64(tlsReloads.WithLabelValues(tlsReloadKindIdentity, "success"))
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "second", certFilename, keyFilename))
+	touch(t, certFilename, time.Now().Add(time.Minute))
+	time.Sleep(2 * time.Millisecond)
+
+	cert, err = tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "second", leafOrganisation(t, cert))
+	cert, err = tlsCfg.GetClientCertificate(&tls.CertificateRequestInfo{
+		SignatureSchemes: []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+		Version:          tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "second", leafOrganisation(t, cert))
+	require.Equal(t, successes+1, promtestutil.ToFloat64(tlsReloads.WithLabelValues(tlsReloadKindIdentity, "success")))
+
+	// A broken certificate is not picked up.
+	failures := promtestutil.ToFloat64(tlsReloads.WithLabelValues(tlsReloadKindIdentity, "failure"))
+	require.NoError(t, ioutil.WriteFile(keyFilename, []byte("not a key"), 0600))
+	touch(t, keyFilename, time.Now().Add(2*time.Minute))
+	time.Sleep(2 * time.Millisecond)
+
+	cert, err = tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "second", leafOrganisation(t, cert))
+	require.Equal(t, failures+1, promtestutil.ToFloat64(tlsReloads.WithLabelValues(tlsReloadKindIdentity, "failure")))
+}
+
+func TestTLSConfigReloadsTrustedCertPool(t *testing.T) {
+	dir := t.TempDir()
+	caFilename := filepath.Join(dir, "ca.pem")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "first", caFilename, filepath.Join(t.TempDir(), "key.pem")))
+
+	peerCertFilename := filepath.Join(t.TempDir(), "peer.pem")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "second", peerCertFilename, filepath.Join(t.TempDir(), "key.pem")))
+	peerCertPEM, err := ioutil.ReadFile(peerCertFilename)
+	require.NoError(t, err)
+	block, _ := pem.Decode(peerCertPEM)
+	peerLeaf, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	cfg := NewTLSConfig("1.2", "1.2", "RequireAndVerifyClientCert", nil, nil)
+	cfg.TrustedCertPool = &TrustedCertPoolConfig{
+		Mode:           NewString(FILEMODE),
+		Encoding:       NewString(PEM),
+		Path:           &caFilename,
+		ReloadInterval: time.Millisecond,
+	}
+
+	tlsCfg, pool, err := makeTLSConfig(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.False(t, tlsCfg.InsecureSkipVerify)
+	_, err = peerLeaf.Verify(x509.VerifyOptions{Roots: pool.clientConfig(tlsCfg).RootCAs, DNSName: "localhost"})
+	require.Error(t, err)
+
+	// Trust the peer.
+	require.NoError(t, ioutil.WriteFile(caFilename, peerCertPEM, 0600))
+	touch(t, caFilename, time.Now().Add(time.Minute))
+	time.Sleep(2 * time.Millisecond)
+
+	_, err = peerLeaf.Verify(x509.VerifyOptions{Roots: pool.clientConfig(tlsCfg).RootCAs, DNSName: "localhost"})
+	require.NoError(t, err)
+	serverCfg, err := tlsCfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Nil(t, serverCfg.GetConfigForClient)
+	_, err = peerLeaf.Verify(x509.VerifyOptions{Roots: serverCfg.ClientCAs, DNSName: "localhost"})
+	require.NoError(t, err)
+}
+
+// startTLSServer starts a server that presents the given certificate.
+func startTLSServer(t *testing.T, certFilename, keyFilename string) *httptest.Server {
+	cert, err := tls.LoadX509KeyPair(certFilename, keyFilename)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.EnableHTTP2 = true
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0) // Rejected handshakes are expected.
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReloadingTrustedCertPoolVerifiesServerAddress(t *testing.T) {
+	dir := t.TempDir()
+	caFilename := filepath.Join(dir, "ca.pem")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "untrusted", caFilename, filepath.Join(dir, "ca.key")))
+
+	// The servers are addressed by IP, which is only in the certificate of the matching server.
+	matchingCert, matchingKey := filepath.Join(dir, "matching.pem"), filepath.Join(dir, "matching.key")
+	require.NoError(t, generateSelfSignedCert([]string{"127.0.0.1"}, "matching", matchingCert, matchingKey))
+	mismatchedCert, mismatchedKey := filepath.Join(dir, "mismatched.pem"), filepath.Join(dir, "mismatched.key")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost", "10.0.0.1"}, "mismatched", mismatchedCert, mismatchedKey))
+	matching := startTLSServer(t, matchingCert, matchingKey)
+	mismatched := startTLSServer(t, mismatchedCert, mismatchedKey)
+
+	cfg := NewTLSConfig("1.2", "1.2", "NoClientCert", []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}, nil)
+	cfg.TrustedCertPool = &TrustedCertPoolConfig{
+		Mode:           NewString(FILEMODE),
+		Encoding:       NewString(PEM),
+		Path:           &caFilename,
+		ReloadInterval: time.Millisecond,
+	}
+	transport, err := defaultHTTPTransport(ctx, &Transport{ClientTLS: cfg, TLSHandshakeTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	tlsCfg, pool, err := makeTLSConfig(ctx, cfg)
+	require.NoError(t, err)
+	creds := &reloadingCredentials{credentials.NewTLS(tlsCfg), tlsCfg, pool}
+
+	get := func(server *httptest.Server) error {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	grpcHandshake := func(server *httptest.Server) error {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _, err = creds.ClientHandshake(context.Background(), server.Listener.Addr().String(), conn)
+		return err
+	}
+
+	// Neither server is trusted.
+	require.Error(t, get(matching))
+	require.Error(t, grpcHandshake(matching))
+
+	// Trust both servers.
+	trusted := []byte{}
+	for _, file := range []string{matchingCert, mismatchedCert} {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		trusted = append(trusted, b...)
+	}
+	require.NoError(t, ioutil.WriteFile(caFilename, trusted, 0600))
+	touch(t, caFilename, time.Now().Add(time.Minute))
+	time.Sleep(2 * time.Millisecond)
+
+	require.NoError(t, get(matching))
+	require.NoError(t, grpcHandshake(matching))
+
+	// The server whose certificate doesn't match its address is rejected, even though it is trusted.
+	err = get(mismatched)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "127.0.0.1")
+	err = grpcHandshake(mismatched)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "127.0.0.1")
+}
+
+func TestReloadingTrustedCertPoolGRPCServerNegotiatesHTTP2(t *testing.T) {
+	dir := t.TempDir()
+	certFilename, keyFilename := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, generateSelfSignedCert([]string{"localhost"}, "server", certFilename, keyFilename))
+
+	cfg := NewTLSConfig("1.2", "1.3", "NoClientCert", nil, []*ServerIdentityConfig{
+		{CertKeyPair: &CertKeyPair{CertPath: &certFilename, KeyPath: &keyFilename}},
+	})
+	cfg.TrustedCertPool = &TrustedCertPoolConfig{
+		Mode:           NewString(FILEMODE),
+		Encoding:       NewString(PEM),
+		Path:           &certFilename,
+		ReloadInterval: time.Millisecond,
+	}
+	opts, err := ExtractGrpcServerOptions(ctx, &GRPCServerConfig{CommonServerConfig: CommonServerConfig{TLS: cfg}})
+	require.NoError(t, err)
+	server := grpc.NewServer(opts...)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // Only the negotiated protocol is checked.
+		NextProtos:         []string{"h2"},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, "h2", conn.ConnectionState().NegotiatedProtocol)
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type authenticatorsKey struct{}
+
+// authenticators lazily builds and shares the JWT authenticators of a service, one per jwtauth
+// configuration, so that the REST and gRPC authorization rules of every endpoint use the same
+// keys caches and refresh goroutines. The API key and Basic authenticators are shared likewise.
+type authenticators struct {
+	m     sync.Mutex
+	cache map[*jwtauth.Config]*jwtauth.StdAuthenticator
+	creds map[*config.AuthenticationConfig][]credauth.Authenticator
+}
+
+func newAuthenticators() *authenticators {
+	return &authenticators{
+		cache: map[*jwtauth.Config]*jwtauth.StdAuthenticator{},
+		creds: map[*config.AuthenticationConfig][]credauth.Authenticator{},
+	}
+}
+
+// withAuthenticators puts the given authenticators in the context.
+func withAuthenticators(ctx context.Context, a *authenticators) context.Context {
+	return context.WithValue(ctx, authenticatorsKey{}, a)
+}
+
+// getAuthenticators returns the authenticators in the context, or nil.
+func getAuthenticators(ctx context.Context) *authenticators {
+	a, _ := ctx.Value(authenticatorsKey{}).(*authenticators)
+	return a
+}
+
+// jwtAuthenticator returns the JWT authenticator for the given configuration, building it on
+// first use. The authenticator is shared through the context when NewServer has set it up,
+// otherwise a new one is built on every call.
+func jwtAuthenticator(ctx context.Context, cfg *jwtauth.Config) (jwtauth.Authenticator, error) {
+	a := getAuthenticators(ctx)
+	if a == nil {
+		return buildJWTAuthenticator(ctx, cfg)
+	}
+	a.m.Lock()
+	defer a.m.Unlock()
+	if auth, ok := a.cache[cfg]; ok {
+		return auth, nil
+	}
+	auth, err := buildJWTAuthenticator(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	a.cache[cfg] = auth
+	return auth, nil
+}
+
+func buildJWTAuthenticator(ctx context.Context, cfg *jwtauth.Config) (*jwtauth.StdAuthenticator, error) {
+	// TODO(fletcher) inject custom http client instrumented with monitoring
+	httpClient, err := config.DefaultHTTPClient(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClientFactory := func(_ string) *http.Client {
+		return httpClient
+	}
+	auth, err := jwtauth.AuthFromConfig(ctx, cfg, httpClientFactory)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf(ctx, "created JWT authenticator for %d issuer(s)", len(cfg.Issuers))
+	return auth, nil
+}
+
+// credentialAuthenticators returns the API key and Basic authenticators for the given
+// configuration (if configured), building them on first use. They are shared through the context
+// as jwtAuthenticator shares the JWT authenticator.
+func credentialAuthenticators(ctx context.Context, cfg *config.AuthenticationConfig) ([]credauth.Authenticator, error) {
+	a := getAuthenticators(ctx)
+	if a == nil {
+		return buildCredentialAuthenticators(cfg)
+	}
+	a.m.Lock()
+	defer a.m.Unlock()
+	if auths, ok := a.creds[cfg]; ok {
+		return auths, nil
+	}
+	auths, err := buildCredentialAuthenticators(cfg)
+	if err != nil {
+		return nil, err
+	}
+	a.creds[cfg] = auths
+	return auths, nil
+}
+
+func buildCredentialAuthenticators(cfg *config.AuthenticationConfig) ([]credauth.Authenticator, error) {
+	var auths []credauth.Authenticator
+	if cfg.APIKey != nil {
+		auth, err := credauth.NewAPIKeyAuthenticator(cfg.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+	if cfg.Basic != nil {
+		auth, err := credauth.NewBasicAuthenticator(cfg.Basic)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+	return auths, nil
+}
+
+// CacheStats returns the cache stats of every issuer of the authenticators built so far, keyed
+// by issuer name.
+func (a *authenticators) CacheStats() map[string]jwtauth.CacheStats {
+	a.m.Lock()
+	defer a.m.Unlock()
+	stats := map[string]jwtauth.CacheStats{}
+	for _, auth := range a.cache {
+		for issuer, s := range auth.CacheStats() {
+			stats[issuer] = s
+		}
+	}
+	return stats
+}
+
+var (
+	jwksCacheLookupsDesc = prometheus.NewDesc(
+		"jwtauth_jwks_cache_lookups_total",
+		"JWKS cache key lookups, by issuer and result",
+		[]string{"issuer", "result"}, nil,
+	)
+	jwksCacheRefreshesDesc = prometheus.NewDesc(
+		"jwtauth_jwks_cache_refreshes_total",
+		"JWKS fetches from the issuer, by issuer and result",
+		[]string{"issuer", "result"}, nil,
+	)
+	jwksCacheKeysDesc = prometheus.NewDesc(
+		"jwtauth_jwks_cache_keys",
+		"Keys currently held in the JWKS cache, by issuer",
+		[]string{"issuer"}, nil,
+	)
+	jwksCacheLastRefreshDesc = prometheus.NewDesc(
+		"jwtauth_jwks_cache_last_refresh_timestamp_seconds",
+		"Time of the last successful JWKS fetch from the issuer, by issuer",
+		[]string{"issuer"}, nil,
+	)
+)
+
+// Describe implements prometheus.Collector.
+func (a *authenticators) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jwksCacheLookupsDesc
+	ch <- jwksCacheRefreshesDesc
+	ch <- jwksCacheKeysDesc
+	ch <- jwksCacheLastRefreshDesc
+}
+
+// Collect implements prometheus.Collector, reporting the cache stats at the time of collection.
+func (a *authenticators) Collect(ch chan<- prometheus.Metric) {
+	stats := a.CacheStats()
+	issuers := make([]string, 0, len(stats))
+	for issuer := range stats {
+		issuers = append(issuers, issuer)
+	}
+	sort.Strings(issuers)
+	for _, issuer := range issuers {
+		s := stats[issuer]
+		ch <- prometheus.MustNewConstMetric(jwksCacheLookupsDesc, prometheus.CounterValue, float64(s.Hits), issuer, "hit")
+		ch <- prometheus.MustNewConstMetric(jwksCacheLookupsDesc, prometheus.CounterValue, float64(s.Misses), issuer, "miss")
+		ch <- prometheus.MustNewConstMetric(jwksCacheRefreshesDesc, prometheus.CounterValue, float64(s.Refreshes), issuer, "success")
+		ch <- prometheus.MustNewConstMetric(jwksCacheRefreshesDesc, prometheus.CounterValue, float64(s.RefreshErrors), issuer, "failure")
+		ch <- prometheus.MustNewConstMetric(jwksCacheKeysDesc, prometheus.GaugeValue, float64(s.Keys), issuer)
+		if !s.LastRefresh.IsZero() {
+			ch <- prometheus.MustNewConstMetric(jwksCacheLastRefreshDesc, prometheus.GaugeValue, float64(s.LastRefresh.UnixNano())/1e9, issuer)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/jsontime"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/testutil"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAuthorizationRuleSharesAuthenticator(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer server.Close()
+
+	ctx := config.PutDefaultConfig(testutil.NewTestContext(), &config.DefaultConfig{
+		Library: config.LibraryConfig{
+			Authentication: &config.AuthenticationConfig{
+				JWTAuth: &jwtauth.Config{Issuers: []jwtauth.IssuerConfig{{
+					Name:     "test-issuer",
+					JWKSURL:  server.URL,
+					CacheTTL: jsontime.Duration(time.Minute),
+				}}},
+			},
+		},
+	})
+	auths := newAuthenticators()
+	ctx = withAuthenticators(ctx, auths)
+
+	hooks := &Hooks{}
+	for _, endpoint := range []string{"a", "b", "c"} {
+		_, err := ResolveRESTAuthorizationRule(ctx, hooks, endpoint, `jwtHasScope("read")`)
+		require.NoError(t, err)
+		_, err = ResolveGRPCAuthorizationRule(ctx, hooks, endpoint, `jwtHasScope("read")`)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	require.Len(t, auths.cache, 1)
+
+	stats := auths.CacheStats()
+	require.Equal(t, uint64(1), stats["test-issuer"].Refreshes)
+	require.Equal(t, 6, promtestutil.CollectAndCount(auths))
+}
+
+func TestResolveAuthorizationRuleRequiresConfig(t *testing.T) {
+	ctx := config.PutDefaultConfig(testutil.NewTestContext(), &config.DefaultConfig{})
+	_, err := ResolveRESTAuthorizationRule(withAuthenticators(ctx, newAuthenticators()), &Hooks{}, "a", `jwtHasScope("read")`)
+	require.EqualError(t, err, "method/endpoint a requires an authorization rule, but there is no config for library.authentication.jwtauth, apiKey or basic")
+}
+
+func TestResolveAuthorizationRuleSharesCredentialAuthenticators(t *testing.T) {
+	secret := config.NewSensitiveString("65803be0872fa538d3ac513edadd6699f54dd8b4a566ee1cf6b185c8cc803949")
+	ctx := config.PutDefaultConfig(testutil.NewTestContext(), &config.DefaultConfig{
+		Library: config.LibraryConfig{
+			Authentication: &config.AuthenticationConfig{
+				APIKey: &credauth.APIKeyConfig{Keys: []credauth.APIKeyCredential{{Name: "partner-a", Hash: &secret}}},
+			},
+		},
+	})
+	auths := newAuthenticators()
+	ctx = withAuthenticators(ctx, auths)
+
+	for _, endpoint := range []string{"a", "b"} {
+		_, err := ResolveRESTAuthorizationRule(ctx, &Hooks{}, endpoint, `jwtHasScope("read")`)
+		require.NoError(t, err)
+	}
+	require.Len(t, auths.cache, 0)
+	require.Len(t, auths.creds, 1)
+	for _, creds := range auths.creds {
+		require.Len(t, creds, 1)
+	}
+}
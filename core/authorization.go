@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anz-bank/sysl-go/config"
+)
+
+type authorizationRulesKey struct{}
+
+// authorizationRules records the effective authorization rule expression of each endpoint of a
+// service, after the rules of the library.authorization config have been applied, for the
+// status endpoint and to detect configured rules for unknown endpoints.
+type authorizationRules struct {
+	m     sync.Mutex
+	rules map[string]string
+}
+
+func newAuthorizationRules() *authorizationRules {
+	return &authorizationRules{rules: map[string]string{}}
+}
+
+// withAuthorizationRules puts the given authorization rules in the context.
+func withAuthorizationRules(ctx context.Context, r *authorizationRules) context.Context {
+	return context.WithValue(ctx, authorizationRulesKey{}, r)
+}
+
+// getAuthorizationRules returns the authorization rules in the context, or nil.
+func getAuthorizationRules(ctx context.Context) *authorizationRules {
+	r, _ := ctx.Value(authorizationRulesKey{}).(*authorizationRules)
+	return r
+}
+
+// effectiveAuthorizationRule returns the authorization rule expression of the named endpoint,
+// given the rule expression declared in the sysl specification, and records it in the
+// authorization rules in the context (if any).
+func effectiveAuthorizationRule(ctx context.Context, cfg *config.DefaultConfig, endpointName string, declared string) string {
+	rule := declared
+	if cfg != nil {
+		rule = cfg.Library.Authorization.Rule(endpointName, declared)
+	}
+	if r := getAuthorizationRules(ctx); r != nil {
+		r.m.Lock()
+		defer r.m.Unlock()
+		r.rules[endpointName] = rule
+	}
+	return rule
+}
+
+// Rules returns a copy of the effective authorization rule expressions, keyed by endpoint name.
+func (r *authorizationRules) Rules() map[string]string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	rules := make(map[string]string, len(r.rules))
+	for name, rule := range r.rules {
+		rules[name] = rule
+	}
+	return rules
+}
+
+// checkConfigured returns an error if the config has a rule for an endpoint that has no
+// recorded authorization rule, matching the names case-insensitively.
+func (r *authorizationRules) checkConfigured(cfg *config.AuthorizationConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	// Config keys are not case-sensitive (e.g. viper lowercases them), so neither are the names.
+	known := make(map[string]bool, len(r.rules))
+	for name := range r.rules {
+		known[strings.ToLower(name)] = true
+	}
+	var unknown []string
+	for name := range cfg.Rules {
+		if !known[strings.ToLower(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("library.authorization.rules: no method/endpoint with an authorization rule named %v", unknown)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAuthorizationRuleFromConfig(t *testing.T) {
+	secret := config.NewSensitiveString("secret")
+	ctx := config.PutDefaultConfig(testutil.NewTestContext(), &config.DefaultConfig{
+		Library: config.LibraryConfig{
+			Authentication: &config.AuthenticationConfig{
+				JWTAuth: &jwtauth.Config{Issuers: []jwtauth.IssuerConfig{{
+					Name:         "test-issuer",
+					SharedSecret: &secret,
+				}}},
+			},
+			Authorization: &config.AuthorizationConfig{Rules: map[string]config.AuthorizationRuleConfig{
+				"GetA": {Expression: `jwtHasScope("override")`},
+				"GetB": {Expression: `jwtHasScope("extra")`, Mode: config.AuthorizationRuleModeSupplement},
+			}},
+		},
+	})
+	rules := newAuthorizationRules()
+	ctx = withAuthorizationRules(ctx, rules)
+
+	hooks := &Hooks{}
+	for _, endpoint := range []string{"GetA", "GetB", "GetC"} {
+		_, err := ResolveRESTAuthorizationRule(ctx, hooks, endpoint, `jwtHasScope("declared")`)
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]string{
+		"GetA": `jwtHasScope("override")`,
+		"GetB": `all(jwtHasScope("declared"),jwtHasScope("extra"))`,
+		"GetC": `jwtHasScope("declared")`,
+	}, rules.Rules())
+	require.NoError(t, rules.checkConfigured(config.GetDefaultConfig(ctx).Library.Authorization))
+
+	require.EqualError(t,
+		rules.checkConfigured(&config.AuthorizationConfig{Rules: map[string]config.AuthorizationRuleConfig{
+			"GetA": {Expression: `jwtHasScope("a")`},
+			"GetX": {Expression: `jwtHasScope("x")`},
+			"GetY": {Expression: `jwtHasScope("y")`},
+		}}),
+		"library.authorization.rules: no method/endpoint with an authorization rule named [GetX GetY]")
+}
+
+func TestAuthorizationRulesFromConfigFile(t *testing.T) {
+	// Config keys are lowercased when loaded, so endpoint names must match case-insensitively.
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`---
+library:
+  authorization:
+    rules:
+      GetPetList:
+        expression: 'jwtHasScope("override")'
+`), 0600))
+	cfg := &config.DefaultConfig{}
+	require.NoError(t, config.NewConfigReaderBuilder().WithConfigFile(configFile).Build().Unmarshal(cfg))
+	require.NoError(t, cfg.Library.Authorization.Validate())
+
+	ctx := config.PutDefaultConfig(testutil.NewTestContext(), cfg)
+	rules := newAuthorizationRules()
+	ctx = withAuthorizationRules(ctx, rules)
+	require.Equal(t, `jwtHasScope("override")`, effectiveAuthorizationRule(ctx, cfg, "GetPetList", `jwtHasScope("declared")`))
+	require.NoError(t, rules.checkConfigured(cfg.Library.Authorization))
+}
@@ -0,0 +1,140 @@
+package authrules
+
+import (
+	"context"
+	"time"
+
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Authorization decisions, as reported in audit events and metrics.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+// AuditEvent describes the authorization decision for a request.
+type AuditEvent struct {
+	Time time.Time
+	// Endpoint is the name of the method or endpoint the request is for.
+	Endpoint string
+	// Rule is the authorization rule expression of the endpoint.
+	Rule string
+	// Decision is DecisionAllow or DecisionDeny.
+	Decision string
+	// Subject and Issuer are the sub and iss claims of the JWT of the request, if it was authenticated.
+	Subject string
+	Issuer  string
+	// FailedExpression is the sub-expression of the rule that caused a denial, if the rule was evaluated.
+	FailedExpression string
+	// Err is the error that caused a denial.
+	Err error
+	// TraceID is the trace ID of the request, if any.
+	TraceID string
+}
+
+// AuditSink receives the audit event of each authorization decision.
+type AuditSink func(ctx context.Context, event AuditEvent)
+
+// LogAuditSink is the default AuditSink. It logs denials at info level and approvals at debug level.
+func LogAuditSink(ctx context.Context, event AuditEvent) {
+	ctx = log.WithStr(ctx, "auth_endpoint", event.Endpoint)
+	ctx = log.WithStr(ctx, "auth_rule", event.Rule)
+	ctx = log.WithStr(ctx, "auth_decision", event.Decision)
+	ctx = log.WithStr(ctx, "auth_subject", event.Subject)
+	ctx = log.WithStr(ctx, "auth_issuer", event.Issuer)
+	if event.TraceID != "" {
+		ctx = log.WithStr(ctx, "traceid", event.TraceID)
+	}
+	if event.Decision == DecisionAllow {
+		log.Debug(ctx, "auth: access allowed")
+		return
+	}
+	if event.FailedExpression != "" {
+		ctx = log.WithStr(ctx, "auth_failed_expression", event.FailedExpression)
+	}
+	if event.Err != nil {
+		ctx = log.WithStr(ctx, "auth_error", event.Err.Error())
+	}
+	log.Info(ctx, "auth: access denied")
+}
+
+var authorizationDecisions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "authorization_decisions_total",
+		Help: "Authorization decisions, by endpoint and decision",
+	},
+	[]string{"endpoint", "decision"},
+)
+
+// AuditCollectors returns the collectors of the authorization decision metrics, for registration
+// against a prometheus registry.
+func AuditCollectors() []prometheus.Collector {
+	return []prometheus.Collector{authorizationDecisions}
+}
+
+// WithAudit wraps an authorization Rule so that each of its decisions is counted and reported to
+// the given sink (or LogAuditSink if nil), with the given endpoint name and rule expression.
+func WithAudit(rule Rule, endpoint string, expression string, sink AuditSink) Rule {
+	if sink == nil {
+		sink = LogAuditSink
+	}
+	return func(ctx context.Context) (context.Context, error) {
+		record := &auditRecord{}
+		resultCtx, err := rule(context.WithValue(ctx, auditRecordKey{}, record))
+
+		event := AuditEvent{
+			Time:             time.Now(),
+			Endpoint:         endpoint,
+			Rule:             expression,
+			Decision:         DecisionAllow,
+			FailedExpression: record.failedExpression,
+			Err:              err,
+		}
+		if err != nil {
+			event.Decision = DecisionDeny
+		}
+		if sub, ok := record.claims["sub"].(string); ok {
+			event.Subject = sub
+		}
+		if iss, ok := record.claims["iss"].(string); ok {
+			event.Issuer = iss
+		}
+		if traceID, ok := common.TryGetTraceIDFromContext(ctx); ok {
+			event.TraceID = traceID.String()
+		}
+		authorizationDecisions.WithLabelValues(endpoint, event.Decision).Inc()
+		sink(ctx, event)
+		return resultCtx, err
+	}
+}
+
+type auditRecordKey struct{}
+
+// auditRecord collects the details of an authorization decision while the rule is evaluated.
+type auditRecord struct {
+	claims           jwtauth.Claims
+	failedExpression string
+}
+
+func getAuditRecord(ctx context.Context) *auditRecord {
+	record, _ := ctx.Value(auditRecordKey{}).(*auditRecord)
+	return record
+}
+
+// recordClaims records the authenticated claims of the request for the audit event, if audited.
+func recordClaims(ctx context.Context, claims jwtauth.Claims) {
+	if record := getAuditRecord(ctx); record != nil {
+		record.claims = claims
+	}
+}
+
+// recordFailedExpression records the sub-expression that caused a denial for the audit event, if audited.
+func recordFailedExpression(ctx context.Context, expression string) {
+	if record := getAuditRecord(ctx); record != nil {
+		record.failedExpression = expression
+	}
+}
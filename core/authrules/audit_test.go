@@ -0,0 +1,73 @@
+package authrules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/google/uuid"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+type claimsAuthenticator jwtauth.Claims
+
+func (a claimsAuthenticator) Authenticate(context.Context, string) (jwtauth.Claims, error) {
+	return jwtauth.Claims(a), nil
+}
+
+func TestWithAudit(t *testing.T) {
+	const expression = `any(jwtHasScope("read"), jwtHasScope("admin"))`
+	claimsRule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(expression)
+	require.NoError(t, err)
+
+	var events []AuditEvent
+	sink := func(_ context.Context, event AuditEvent) { events = append(events, event) }
+
+	traceID := uuid.New()
+	ctx := metadata.NewIncomingContext(testutil.NewTestContext(), metadata.Pairs("authorization", "Bearer token"))
+	ctx = common.AddTraceIDToContext(ctx, traceID, true)
+
+	allowed := promtestutil.ToFloat64(authorizationDecisions.WithLabelValues("TestWithAudit", DecisionAllow))
+	denied := promtestutil.ToFloat64(authorizationDecisions.WithLabelValues("TestWithAudit", DecisionDeny))
+
+	for _, scope := range []string{"read", "write"} {
+		rule, err := MakeGRPCJWTAuthorizationRule(claimsRule, claimsAuthenticator{"sub": "alice", "iss": "issuer", "scope": scope})
+		require.NoError(t, err)
+		_, _ = WithAudit(rule, "TestWithAudit", expression, sink)(ctx)
+	}
+
+	require.Len(t, events, 2)
+	require.Equal(t, DecisionAllow, events[0].Decision)
+	require.NoError(t, events[0].Err)
+	require.Equal(t, "", events[0].FailedExpression)
+	require.Equal(t, DecisionDeny, events[1].Decision)
+	require.Error(t, events[1].Err)
+	require.NotEmpty(t, events[1].FailedExpression)
+	for _, event := range events {
+		require.Equal(t, "TestWithAudit", event.Endpoint)
+		require.Equal(t, expression, event.Rule)
+		require.Equal(t, "alice", event.Subject)
+		require.Equal(t, "issuer", event.Issuer)
+		require.Equal(t, traceID.String(), event.TraceID)
+	}
+
+	require.Equal(t, allowed+1, promtestutil.ToFloat64(authorizationDecisions.WithLabelValues("TestWithAudit", DecisionAllow)))
+	require.Equal(t, denied+1, promtestutil.ToFloat64(authorizationDecisions.WithLabelValues("TestWithAudit", DecisionDeny)))
+}
+
+func TestWithAuditUnauthenticated(t *testing.T) {
+	rule, err := MakeGRPCJWTAuthorizationRule(nil, claimsAuthenticator{})
+	require.NoError(t, err)
+
+	var event AuditEvent
+	_, err = WithAudit(rule, "TestWithAuditUnauthenticated", `jwtHasScope("read")`, func(_ context.Context, e AuditEvent) { event = e })(testutil.NewTestContext())
+	require.Error(t, err)
+	require.Equal(t, DecisionDeny, event.Decision)
+	require.Equal(t, "", event.Subject)
+	require.Equal(t, "", event.TraceID)
+	require.Equal(t, err, event.Err)
+}
@@ -0,0 +1,68 @@
+package authrules
+
+import (
+	"context"
+
+	"github.com/anz-bank/sysl-go/authexpr"
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/log"
+	"google.golang.org/grpc/status"
+)
+
+// ExplainRESTDenials wraps an authorization Rule so that, when it denies access because its
+// expression evaluated to false, the evaluation trace of the expression is included in the
+// "authorization" field of the error response.
+//
+// The trace reveals the authorization rule, so this should not be used in production.
+func ExplainRESTDenials(rule Rule) Rule {
+	return withExplanation(rule, func(err error, trace *authexpr.Trace) error {
+		return common.WrappedError(err, common.KV{K: "authorization", V: trace})
+	})
+}
+
+// ExplainGRPCDenials wraps an authorization Rule so that, when it denies access because its
+// expression evaluated to false, the evaluation trace of the expression is appended to the
+// message of the error status.
+//
+// The trace reveals the authorization rule, so this should not be used in production.
+func ExplainGRPCDenials(rule Rule) Rule {
+	return withExplanation(rule, func(err error, trace *authexpr.Trace) error {
+		st := status.Convert(err)
+		return status.Errorf(st.Code(), "%s:\n%s", st.Message(), trace)
+	})
+}
+
+func withExplanation(rule Rule, explain func(err error, trace *authexpr.Trace) error) Rule {
+	return func(ctx context.Context) (context.Context, error) {
+		ctx, record := withExplainRecord(ctx)
+		resultCtx, err := rule(ctx)
+		if err != nil && record.trace != nil {
+			log.Debugf(ctx, "auth: evaluation of the denying authorization rule:\n%s", record.trace)
+			err = explain(err, record.trace)
+		}
+		return resultCtx, err
+	}
+}
+
+type explainRecordKey struct{}
+
+type explainRecord struct {
+	trace *authexpr.Trace
+}
+
+// withExplainRecord returns the context with a record for the evaluation trace of a denial, or the
+// given context if it already has one.
+func withExplainRecord(ctx context.Context) (context.Context, *explainRecord) {
+	if record, ok := ctx.Value(explainRecordKey{}).(*explainRecord); ok {
+		return ctx, record
+	}
+	record := &explainRecord{}
+	return context.WithValue(ctx, explainRecordKey{}, record), record
+}
+
+// getExplainRecord returns the record for the evaluation trace of a denial, or nil if the denial
+// is not to be explained.
+func getExplainRecord(ctx context.Context) *explainRecord {
+	record, _ := ctx.Value(explainRecordKey{}).(*explainRecord)
+	return record
+}
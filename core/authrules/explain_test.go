@@ -0,0 +1,94 @@
+package authrules
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExplainRESTDenials(t *testing.T) {
+	claimsRule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(`all(jwtHasScope("read"), jwtIssuerIs("issuer"))`)
+	require.NoError(t, err)
+	rule, err := MakeRESTJWTAuthorizationRule(claimsRule, claimsAuthenticator{"iss": "issuer", "scope": "write"})
+	require.NoError(t, err)
+
+	ctx := common.RequestHeaderToContext(testutil.NewTestContext(), http.Header{"Authorization": {"Bearer token"}})
+	_, err = ExplainRESTDenials(rule)(ctx)
+	require.Error(t, err)
+
+	w := httptest.NewRecorder()
+	common.HandleError(ctx, w, common.UnauthorizedError, "Auth error", err, nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body struct {
+		Status struct {
+			Authorization struct {
+				Op     string `json:"op"`
+				Result bool   `json:"result"`
+				Args   []struct {
+					Expr   string `json:"expr"`
+					Result bool   `json:"result"`
+				} `json:"args"`
+			} `json:"authorization"`
+		} `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	trace := body.Status.Authorization
+	require.Equal(t, "all", trace.Op)
+	require.False(t, trace.Result)
+	require.Len(t, trace.Args, 2)
+	require.Equal(t, `jwtHasScope("read")`, trace.Args[0].Expr)
+	require.False(t, trace.Args[0].Result)
+	require.True(t, trace.Args[1].Result)
+}
+
+func TestExplainGRPCDenials(t *testing.T) {
+	claimsRule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(`jwtHasScope("read")`)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(testutil.NewTestContext(), metadata.Pairs("authorization", "Bearer token"))
+
+	rule, err := MakeGRPCJWTAuthorizationRule(claimsRule, claimsAuthenticator{"scope": "write"})
+	require.NoError(t, err)
+	_, err = ExplainGRPCDenials(rule)(ctx)
+	st := status.Convert(err)
+	require.Equal(t, codes.PermissionDenied, st.Code())
+	require.Equal(t, "insufficient permissions:\njwtHasScope(\"read\") => false (claim \"scope\"=\"write\")\n", st.Message())
+
+	rule, err = MakeGRPCJWTAuthorizationRule(claimsRule, claimsAuthenticator{"scope": "read"})
+	require.NoError(t, err)
+	_, err = ExplainGRPCDenials(rule)(ctx)
+	require.NoError(t, err)
+}
+
+func TestJWTClaimsRuleOnlyTracesExplainedDenials(t *testing.T) {
+	claimsRule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(`all(jwtHasScope("read"), jwtIssuerIs("issuer"))`)
+	require.NoError(t, err)
+	claims := map[string]interface{}{"iss": "issuer", "scope": "write"}
+
+	// Without an explain record, the denial is not traced.
+	ctx := testutil.NewTestContext()
+	result, err := claimsRule(ctx, claims)
+	require.NoError(t, err)
+	require.False(t, result)
+	require.Nil(t, getExplainRecord(ctx))
+
+	// With an explain record, the denial is traced and its cause is derived from the trace.
+	ctx, record := withExplainRecord(ctx)
+	audit := &auditRecord{}
+	ctx = context.WithValue(ctx, auditRecordKey{}, audit)
+	result, err = claimsRule(ctx, claims)
+	require.NoError(t, err)
+	require.False(t, result)
+	require.NotNil(t, record.trace)
+	require.Equal(t, `jwtHasScope("read")`, audit.failedExpression)
+}
@@ -2,15 +2,22 @@ package authrules
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/anz-bank/sysl-go/log"
 
 	"github.com/anz-bank/sysl-go/authexpr"
 	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/credauth"
 	"github.com/anz-bank/sysl-go/jwtauth"
 	"github.com/anz-bank/sysl-go/jwtauth/jwtgrpc"
+	"github.com/go-chi/chi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // ClaimsBasedAuthorizationRule decides if access is approved or denied based on the given claims.
@@ -28,42 +35,154 @@ func MakeDefaultJWTClaimsBasedAuthorizationRule(authorizationRuleExpression stri
 	return func(ctx context.Context, claims jwtauth.Claims) (bool, error) {
 		evalCtx := authexpr.EvaluationContext{
 			JWTHasScope: authexpr.MakeStandardJWTHasScope(claims),
+			Claims:      claims,
+			Request:     requestAttributesFromContext(ctx),
 		}
-		return rootExpr.Evaluate(evalCtx)
+		// Only trace the evaluation when the denial is to be explained, to keep evaluation cheap.
+		if record := getExplainRecord(ctx); record != nil {
+			trace, err := rootExpr.Explain(evalCtx)
+			if err != nil {
+				return false, err
+			}
+			if !trace.Result {
+				record.trace = trace
+				recordFailedExpression(ctx, trace.Cause())
+			}
+			return trace.Result, nil
+		}
+		result, cause, err := rootExpr.EvaluateWithCause(evalCtx)
+		if err == nil && !result {
+			recordFailedExpression(ctx, cause)
+		}
+		return result, err
 	}, nil
 }
 
+// requestAttributesFromContext returns the attributes of the REST or gRPC request being authorized.
+func requestAttributesFromContext(ctx context.Context) authexpr.RequestAttributes {
+	var tlsPeer *authexpr.TLSPeer
+	if id := common.PeerIdentityFromContext(ctx); id != nil {
+		tlsPeer = &authexpr.TLSPeer{Subject: id.Subject, SANs: id.SANs(), SPIFFEID: id.SPIFFEID}
+	}
+	if method, u := common.RequestMethodAndURLFromContext(ctx); u != nil {
+		attrs := authexpr.RequestAttributes{
+			Method:      method,
+			QueryParams: u.Query(),
+			Header:      common.RequestHeaderFromContext(ctx),
+			TLSPeer:     tlsPeer,
+		}
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			attrs.PathParams = make(map[string]string, len(rctx.URLParams.Keys))
+			for i, key := range rctx.URLParams.Keys {
+				attrs.PathParams[key] = rctx.URLParams.Values[i]
+			}
+		}
+		return attrs
+	}
+	method, _ := grpc.Method(ctx)
+	attrs := authexpr.RequestAttributes{Method: method, TLSPeer: tlsPeer}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		attrs.Header = metadataToHeader(md)
+	}
+	return attrs
+}
+
+// metadataToHeader returns the gRPC metadata as an http.Header.
+func metadataToHeader(md metadata.MD) http.Header {
+	header := make(http.Header, len(md))
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return header
+}
+
 // MakeGRPCAuthorizationRule creates an authorization Rule from a claims-based authorization Rule
 // and a jwtauth Authenticator.
 func MakeGRPCJWTAuthorizationRule(authRule JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator) (Rule, error) {
-	return func(ctx context.Context) (context.Context, error) {
-		rawToken, err := jwtgrpc.GetBearerFromIncomingContext(ctx)
-		if err != nil {
-			log.Debugf(ctx, "auth: error extracting jwt from context: %v", err)
-			return nil, err
-		}
-		return authorize(ctx, rawToken, authRule, authenticator)
-	}, nil
+	return MakeGRPCAuthorizationRule(authRule, authenticator)
 }
 
 // MakeRESTJWTAuthorizationRule creates an authorization Rule from a claims-based authorization Rule
 // and a jwtauth Authenticator.
 func MakeRESTJWTAuthorizationRule(authRule JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator) (Rule, error) {
+	return MakeRESTAuthorizationRule(authRule, authenticator)
+}
+
+// MakeGRPCAuthorizationRule creates an authorization Rule from a claims-based authorization Rule
+// that authenticates calls with a JWT bearer token (if the jwtauth Authenticator is not nil) or
+// with the credentials of any of the given credauth Authenticators.
+func MakeGRPCAuthorizationRule(authRule JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator, credAuthenticators ...credauth.Authenticator) (Rule, error) {
 	return func(ctx context.Context) (context.Context, error) {
-		rawToken, err := getBearerTokenFromIncomingRESTContext(ctx)
-		if err != nil {
-			log.Debugf(ctx, "auth: error extracting jwt from context: %v", err)
-			return nil, err
+		var tokenErr error = jwtgrpc.ErrNoAuthHeader
+		if authenticator != nil {
+			rawToken, err := jwtgrpc.GetBearerFromIncomingContext(ctx)
+			if err == nil {
+				claims, err := authenticator.Authenticate(ctx, rawToken)
+				return authorize(ctx, claims, err, authRule)
+			}
+			tokenErr = err
+		}
+		var header http.Header
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			header = metadataToHeader(md)
 		}
-		return authorize(ctx, rawToken, authRule, authenticator)
+		return authorizeCredentials(ctx, header, nil, authRule, credAuthenticators, tokenErr)
 	}, nil
 }
 
-func authorize(ctx context.Context, rawToken string, authRule JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator) (context.Context, error) {
-	claims, err := authenticator.Authenticate(ctx, rawToken)
-	if err != nil {
-		log.Debugf(ctx, "auth: jwt authentication failed, access denied: %v", err)
-		return ctx, err
+// MakeRESTAuthorizationRule creates an authorization Rule from a claims-based authorization Rule
+// that authenticates requests with a JWT bearer token (if the jwtauth Authenticator is not nil) or
+// with the credentials of any of the given credauth Authenticators.
+func MakeRESTAuthorizationRule(authRule JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator, credAuthenticators ...credauth.Authenticator) (Rule, error) {
+	return func(ctx context.Context) (context.Context, error) {
+		var tokenErr error = errNoCredentials
+		if authenticator != nil {
+			rawToken, err := getBearerTokenFromIncomingRESTContext(ctx)
+			if err == nil {
+				claims, err := authenticator.Authenticate(ctx, rawToken)
+				return authorize(ctx, claims, err, authRule)
+			}
+			tokenErr = err
+		}
+		var query url.Values
+		if _, u := common.RequestMethodAndURLFromContext(ctx); u != nil {
+			query = u.Query()
+		}
+		return authorizeCredentials(ctx, common.RequestHeaderFromContext(ctx), query, authRule, credAuthenticators, tokenErr)
+	}, nil
+}
+
+var errNoCredentials = &jwtauth.AuthError{
+	Code:  jwtauth.AuthErrCodeInvalidCredentials,
+	Cause: fmt.Errorf("no credentials"),
+}
+
+// authorizeCredentials authorizes a request with the credentials of the first of the credauth
+// Authenticators that finds credentials of its scheme in the request, or returns noCredentialsErr.
+func authorizeCredentials(ctx context.Context, header http.Header, query url.Values, authRule JWTClaimsBasedAuthorizationRule, credAuthenticators []credauth.Authenticator, noCredentialsErr error) (context.Context, error) {
+	for _, a := range credAuthenticators {
+		claims, err := a.Authenticate(ctx, header, query)
+		if errors.Is(err, credauth.ErrNoCredentials) {
+			continue
+		}
+		return authorize(ctx, claims, err, authRule)
+	}
+	log.Debugf(ctx, "auth: error extracting credentials from context: %v", noCredentialsErr)
+	return nil, noCredentialsErr
+}
+
+// authorize evaluates the claims-based authorization Rule against the claims of an authenticated
+// request, given the result of its authentication.
+func authorize(ctx context.Context, claims jwtauth.Claims, authErr error, authRule JWTClaimsBasedAuthorizationRule) (context.Context, error) {
+	if authErr != nil {
+		log.Debugf(ctx, "auth: authentication failed, access denied: %v", authErr)
+		return ctx, authErr
+	}
+	recordClaims(ctx, claims)
+	if sub, ok := claims["sub"].(string); ok {
+		common.RecordAccessLogSubject(ctx, sub)
 	}
 	isAuthorised, err := authRule(ctx, claims)
 	if err != nil {
@@ -0,0 +1,135 @@
+package authrules
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/config/sensitive"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/jwtauth/jwtgrpc"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestJWTClaimsRuleRESTRequestAttributes(t *testing.T) {
+	rule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(
+		`all(jwtClaimEquals("sub", $pathParam("id")), equals($header("X-Tenant"), $queryParam("tenant")), equals($method(), "GET"))`)
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "alice")
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
+	u, err := url.Parse("http://localhost/users/alice?tenant=t1")
+	require.NoError(t, err)
+	ctx = common.RequestMethodAndURLToContext(ctx, http.MethodGet, u)
+	ctx = common.RequestHeaderToContext(ctx, http.Header{"X-Tenant": {"t1"}})
+
+	allowed, err := rule(ctx, jwtauth.Claims{"sub": "alice"})
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	allowed, err = rule(ctx, jwtauth.Claims{"sub": "bob"})
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
+
+func TestJWTClaimsRuleGRPCRequestAttributes(t *testing.T) {
+	rule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(
+		`all(equals($header("x-tenant"), "t1"), equals($method(), "/pkg.Service/Method"))`)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "t1"))
+	ctx = grpc.NewContextWithServerTransportStream(ctx, testServerTransportStream{method: "/pkg.Service/Method"})
+
+	allowed, err := rule(ctx, jwtauth.Claims{})
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestJWTClaimsRuleTLSPeer(t *testing.T) {
+	rule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(
+		`all(tlsPeerSPIFFEIDIs("spiffe://example.com/client"), jwtClaimEquals("sub", "alice"))`)
+	require.NoError(t, err)
+
+	spiffeID, err := url.Parse("spiffe://example.com/client")
+	require.NoError(t, err)
+	id := common.NewPeerIdentity(&x509.Certificate{URIs: []*url.URL{spiffeID}})
+
+	allowed, err := rule(context.Background(), jwtauth.Claims{"sub": "alice"})
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	allowed, err = rule(common.PeerIdentityToContext(context.Background(), id), jwtauth.Claims{"sub": "alice"})
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+type testServerTransportStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s testServerTransportStream) Method() string {
+	return s.method
+}
+
+func TestAuthorizationRuleCredentials(t *testing.T) {
+	claimsRule, err := MakeDefaultJWTClaimsBasedAuthorizationRule(`jwtHasScope("read")`)
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte("key-123"))
+	secret := sensitive.NewString(hex.EncodeToString(hash[:]))
+	apiKeys, err := credauth.NewAPIKeyAuthenticator(&credauth.APIKeyConfig{
+		Keys: []credauth.APIKeyCredential{{Name: "partner-a", Hash: &secret, Scope: "read"}},
+	})
+	require.NoError(t, err)
+
+	restRule, err := MakeRESTAuthorizationRule(claimsRule, claimsAuthenticator{"sub": "alice", "scope": "read"}, apiKeys)
+	require.NoError(t, err)
+	grpcRule, err := MakeGRPCAuthorizationRule(claimsRule, nil, apiKeys)
+	require.NoError(t, err)
+
+	restCtx := func(header http.Header) context.Context {
+		return common.RequestHeaderToContext(testutil.NewTestContext(), header)
+	}
+	grpcCtx := func(kv ...string) context.Context {
+		return metadata.NewIncomingContext(testutil.NewTestContext(), metadata.Pairs(kv...))
+	}
+
+	ctx, err := restRule(restCtx(http.Header{"Authorization": {"Bearer token"}}))
+	require.NoError(t, err)
+	claims, _ := jwtauth.GetClaimsFromContext(ctx)
+	require.Equal(t, "alice", claims["sub"])
+
+	ctx, err = restRule(restCtx(http.Header{"X-Api-Key": {"key-123"}}))
+	require.NoError(t, err)
+	claims, _ = jwtauth.GetClaimsFromContext(ctx)
+	require.Equal(t, "partner-a", claims["sub"])
+
+	_, err = restRule(restCtx(http.Header{"X-Api-Key": {"key-456"}}))
+	var authErr *jwtauth.AuthError
+	require.True(t, errors.As(err, &authErr))
+	require.Equal(t, jwtauth.AuthErrCodeInvalidCredentials, authErr.Code)
+
+	_, err = restRule(restCtx(http.Header{}))
+	require.True(t, errors.As(err, &authErr))
+	require.Equal(t, jwtauth.AuthErrCodeInvalidJWT, authErr.Code)
+
+	ctx, err = grpcRule(grpcCtx("x-api-key", "key-123"))
+	require.NoError(t, err)
+	claims, _ = jwtauth.GetClaimsFromContext(ctx)
+	require.Equal(t, "partner-a", claims["sub"])
+
+	_, err = grpcRule(grpcCtx("authorization", "Bearer token"))
+	require.Equal(t, jwtgrpc.ErrNoAuthHeader, err)
+}
@@ -1,6 +1,11 @@
 package core
 
-import "github.com/anz-bank/sysl-go/status"
+import (
+	"context"
+	"fmt"
+
+	"github.com/anz-bank/sysl-go/status"
+)
 
 var (
 	// Build metadata. These values are intended to be overridden by values
@@ -38,3 +43,33 @@ var buildMetadata = &status.BuildMetadata{
 	BranchName: BranchName,
 	TagName:    TagName,
 }
+
+// defaultAppName is the application name used when none is provided through
+// WithAppName or at link time.
+const defaultAppName = "nameless-autogenerated-app"
+
+// appName returns the application name held within the context, falling back
+// to the link-time Name and then to defaultAppName.
+func appName(ctx context.Context) string {
+	if name, ok := ctx.Value(serveAppNameKey).(string); ok && name != "" {
+		return name
+	}
+	if Name != "" {
+		return Name
+	}
+	return defaultAppName
+}
+
+// appBuildMetadata returns the build metadata reported for the application,
+// with the name taken from the context.
+func appBuildMetadata(ctx context.Context) *status.BuildMetadata {
+	metadata := *buildMetadata
+	metadata.Name = appName(ctx)
+	return &metadata
+}
+
+// serverName returns the name of a server of the application, prefixed by the
+// application name.
+func serverName(ctx context.Context, server string) string {
+	return fmt.Sprintf("%s %s", appName(ctx), server)
+}
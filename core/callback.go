@@ -7,10 +7,12 @@ import (
 	"net/http"
 
 	"github.com/anz-bank/sysl-go/log"
+	"github.com/anz-bank/sysl-go/tracing"
 
 	"github.com/anz-bank/sysl-go/common"
 	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/core/authrules"
+	"github.com/anz-bank/sysl-go/credauth"
 	"github.com/anz-bank/sysl-go/jwtauth"
 	"github.com/go-chi/chi"
 	"google.golang.org/grpc"
@@ -108,6 +110,11 @@ type Hooks struct {
 	// hook is nil, then authrules.MakeDefaultJWTClaimsBasedAuthorizationRule is used.
 	OverrideMakeJWTClaimsBasedAuthorizationRule func(authorizationRuleExpression string) (authrules.JWTClaimsBasedAuthorizationRule, error)
 
+	// AuthorizationAuditSink receives an audit event for each decision made by an authorization
+	// rule, e.g. to send them to an audit log. By default, if this hook is nil, then
+	// authrules.LogAuditSink is used.
+	AuthorizationAuditSink authrules.AuditSink
+
 	// AddHTTPMiddleware can be used to install additional HTTP middleware into the chi.Router
 	// used to serve all (non-admin) HTTP endpoints. By default, sysl-go installs a number of
 	// HTTP middleware -- refer to prepareMiddleware inside sysl-go/core. This hook can only
@@ -131,6 +138,27 @@ type Hooks struct {
 	// StoppableServerBuilder can be used to add a function which will be used to create the public listener
 	// instead of the normal httpServer. This can be used to create custom test HTTP listeners.
 	StoppableServerBuilder func(ctx context.Context, rootRouter http.Handler, tlsConfig *tls.Config, httpConfig config.CommonHTTPServerConfig, name string) StoppableServer
+
+	// OnStart functions are called in order at the beginning of Start, before any of the servers
+	// are configured. If any of them returns an error, Start is aborted and returns that error.
+	OnStart []func(ctx context.Context) error
+
+	// OnReady functions are called in order once the servers have been started and are accepting
+	// connections, immediately before the health server (if enabled) is marked ready. They can be
+	// used to warm up or prime caches before traffic is routed to the service. If any of them
+	// returns an error, the servers are stopped and Start returns that error.
+	OnReady []func(ctx context.Context) error
+
+	// OnShutdown functions are called in reverse order by Stop or GracefulStop, once the servers
+	// have stopped, or by Start if a server stops by itself. They are called at most once, with a
+	// context that expires after library.lifecycle.shutdownHookTimeout (default 30s). Errors are
+	// collected and returned from Stop or GracefulStop.
+	OnShutdown []func(ctx context.Context) error
+
+	// SpanExporter receives the spans recorded for inbound requests and downstream calls, e.g. to
+	// send them to a tracing backend. By default, if this hook is nil, spans are discarded (but trace
+	// contexts are still propagated). See tracing.InMemoryExporter for an exporter suitable for tests.
+	SpanExporter tracing.Exporter
 }
 
 func ResolveGrpcDialOptions(ctx context.Context, serviceName string, h *Hooks, grpcDownstreamConfig *config.CommonGRPCDownstreamData) ([]grpc.DialOption, error) {
@@ -166,15 +194,17 @@ func ResolveGrpcServerOptions(ctx context.Context, h *Hooks, grpcPublicServerCon
 }
 
 func ResolveGRPCAuthorizationRule(ctx context.Context, h *Hooks, endpointName string, authRuleExpression string) (authrules.Rule, error) {
-	return resolveAuthorizationRule(ctx, h, endpointName, authRuleExpression, authrules.MakeGRPCJWTAuthorizationRule)
+	return resolveAuthorizationRule(ctx, h, endpointName, authRuleExpression, authrules.MakeGRPCAuthorizationRule, authrules.ExplainGRPCDenials)
 }
 
 func ResolveRESTAuthorizationRule(ctx context.Context, h *Hooks, endpointName string, authRuleExpression string) (authrules.Rule, error) {
-	return resolveAuthorizationRule(ctx, h, endpointName, authRuleExpression, authrules.MakeRESTJWTAuthorizationRule)
+	return resolveAuthorizationRule(ctx, h, endpointName, authRuleExpression, authrules.MakeRESTAuthorizationRule, authrules.ExplainRESTDenials)
 }
 
-func resolveAuthorizationRule(ctx context.Context, h *Hooks, endpointName string, authRuleExpression string, ruleFactory func(authRule authrules.JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator) (authrules.Rule, error)) (authrules.Rule, error) {
+func resolveAuthorizationRule(ctx context.Context, h *Hooks, endpointName string, authRuleExpression string, ruleFactory func(authRule authrules.JWTClaimsBasedAuthorizationRule, authenticator jwtauth.Authenticator, credAuthenticators ...credauth.Authenticator) (authrules.Rule, error), explainDenials func(rule authrules.Rule) authrules.Rule) (authrules.Rule, error) {
 	cfg := config.GetDefaultConfig(ctx)
+	// The rule declared in the sysl specification may be overridden or supplemented by config.
+	authRuleExpression = effectiveAuthorizationRule(ctx, cfg, endpointName, authRuleExpression)
 	if cfg.Development != nil && cfg.Development.DisableAllAuthorizationRules {
 		log.Info(ctx, "warning: development.disableAllAuthorizationRules is set, all authorization rules are disabled, this is insecure and should not be used in production.")
 		return authrules.InsecureAlwaysGrantAccess, nil
@@ -191,22 +221,30 @@ func resolveAuthorizationRule(ctx context.Context, h *Hooks, endpointName string
 		return nil, err
 	}
 
-	// TODO(fletcher) inject custom http client instrumented with monitoring
-	httpClient, err := config.DefaultHTTPClient(ctx, nil)
-	if err != nil {
-		return nil, err
+	authCfg := cfg.Library.Authentication
+	if authCfg == nil || (authCfg.JWTAuth == nil && authCfg.APIKey == nil && authCfg.Basic == nil) {
+		return nil, fmt.Errorf("method/endpoint %s requires an authorization rule, but there is no config for library.authentication.jwtauth, apiKey or basic", endpointName)
 	}
-	httpClientFactory := func(_ string) *http.Client {
-		return httpClient
+	// The authenticators and their caches are shared between all the endpoints of the service.
+	var authenticator jwtauth.Authenticator
+	if authCfg.JWTAuth != nil {
+		authenticator, err = jwtAuthenticator(ctx, authCfg.JWTAuth)
+		if err != nil {
+			return nil, err
+		}
 	}
-
-	// Note: this will start a new jwtauth.Authenticator with its own cache & threads running for each of our service's endpoints, we usually want a shared one.
-	if cfg == nil || cfg.Library.Authentication == nil || cfg.Library.Authentication.JWTAuth == nil {
-		return nil, fmt.Errorf("method/endpoint %s requires a JWT-based authorization rule, but there is no config for library.authentication.jwtauth", endpointName)
+	credAuthenticators, err := credentialAuthenticators(ctx, authCfg)
+	if err != nil {
+		return nil, err
 	}
-	authenticator, err := jwtauth.AuthFromConfig(ctx, cfg.Library.Authentication.JWTAuth, httpClientFactory)
+	rule, err := ruleFactory(claimsBasedAuthRule, authenticator, credAuthenticators...)
 	if err != nil {
 		return nil, err
 	}
-	return ruleFactory(claimsBasedAuthRule, authenticator)
+	rule = authrules.WithAudit(rule, endpointName, authRuleExpression, h.AuthorizationAuditSink)
+	if cfg.Development != nil && cfg.Development.ExplainAuthorizationDenials {
+		log.Warn(ctx, "development.explainAuthorizationDenials is set, authorization rules are revealed in the responses to denied requests, this should not be used in production.")
+		rule = explainDenials(rule)
+	}
+	return rule, nil
 }
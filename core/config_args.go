@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const profileFlag = "--profile"
+
+// configArgs holds the command line arguments of an application.
+type configArgs struct {
+	help    bool
+	version bool
+	profile string
+	files   []string
+}
+
+// usage returns the usage message of the application with the given name.
+func usage(cmd string) string {
+	return fmt.Sprintf("%s [--profile name] config [config...] | -h | --help | -v | --version", cmd)
+}
+
+// parseConfigArgs parses the command line arguments (excluding the command itself).
+// The arguments are either a single help or version flag, or an optional profile followed
+// by one or more config files.
+func parseConfigArgs(cmd string, args []string) (configArgs, error) {
+	if len(args) == 1 {
+		switch args[0] {
+		case "--help", "-h":
+			return configArgs{help: true}, nil
+		case "--version", "-v":
+			return configArgs{version: true}, nil
+		}
+	}
+
+	var parsed configArgs
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == profileFlag:
+			if i+1 < len(args) {
+				i++
+				parsed.profile = args[i]
+			}
+			if parsed.profile == "" {
+				return configArgs{}, fmt.Errorf("Missing profile name (usage: %s)", usage(cmd))
+			}
+		case strings.HasPrefix(arg, profileFlag+"="):
+			parsed.profile = strings.TrimPrefix(arg, profileFlag+"=")
+			if parsed.profile == "" {
+				return configArgs{}, fmt.Errorf("Missing profile name (usage: %s)", usage(cmd))
+			}
+		case strings.HasPrefix(arg, "-"):
+			return configArgs{}, fmt.Errorf("Unknown flag %s (usage: %s)", arg, usage(cmd))
+		default:
+			parsed.files = append(parsed.files, arg)
+		}
+	}
+
+	if len(parsed.files) == 0 {
+		return configArgs{}, fmt.Errorf("Wrong number of arguments (usage: %s)", usage(cmd))
+	}
+	return parsed, nil
+}
+
+// profileConfigFiles returns the given config files, each followed by its profile-specific
+// overlay if one exists. The overlay of the file dir/name.ext for the profile p is the file
+// dir/name.p.ext. An error is returned if a profile is given but no overlay exists.
+func profileConfigFiles(fs afero.Fs, files []string, profile string) ([]string, error) {
+	if profile == "" {
+		return files, nil
+	}
+
+	result := make([]string, 0, 2*len(files))
+	found := false
+	for _, file := range files {
+		result = append(result, file)
+		ext := filepath.Ext(file)
+		overlay := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(file, ext), profile, ext)
+		exists, err := afero.Exists(fs, overlay)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result = append(result, overlay)
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no config files found for profile %s", profile)
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected configArgs
+		err      string
+	}{
+		{name: "help", args: []string{"-h"}, expected: configArgs{help: true}},
+		{name: "version", args: []string{"--version"}, expected: configArgs{version: true}},
+		{name: "single file", args: []string{"config.yaml"}, expected: configArgs{files: []string{"config.yaml"}}},
+		{name: "multiple files", args: []string{"base.yaml", "prod.yaml"}, expected: configArgs{files: []string{"base.yaml", "prod.yaml"}}},
+		{name: "profile", args: []string{"--profile", "prod", "base.yaml"}, expected: configArgs{profile: "prod", files: []string{"base.yaml"}}},
+		{name: "profile with equals", args: []string{"base.yaml", "--profile=prod"}, expected: configArgs{profile: "prod", files: []string{"base.yaml"}}},
+		{name: "no args", args: []string{}, err: "Wrong number of arguments (usage: app [--profile name] config [config...] | -h | --help | -v | --version)"},
+		{name: "profile only", args: []string{"--profile", "prod"}, err: "Wrong number of arguments (usage: app [--profile name] config [config...] | -h | --help | -v | --version)"},
+		{name: "missing profile", args: []string{"base.yaml", "--profile"}, err: "Missing profile name (usage: app [--profile name] config [config...] | -h | --help | -v | --version)"},
+		{name: "unknown flag", args: []string{"-x", "base.yaml"}, err: "Unknown flag -x (usage: app [--profile name] config [config...] | -h | --help | -v | --version)"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := parseConfigArgs("app", tt.args)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestProfileConfigFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config/base.yaml", []byte("a: 1"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "config/base.prod.yaml", []byte("a: 2"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "secrets.yaml", []byte("b: 1"), 0644))
+
+	files, err := profileConfigFiles(fs, []string{"config/base.yaml", "secrets.yaml"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"config/base.yaml", "secrets.yaml"}, files)
+
+	files, err = profileConfigFiles(fs, []string{"config/base.yaml", "secrets.yaml"}, "prod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"config/base.yaml", "config/base.prod.yaml", "secrets.yaml"}, files)
+
+	_, err = profileConfigFiles(fs, []string{"config/base.yaml", "secrets.yaml"}, "test")
+	require.EqualError(t, err, "no config files found for profile test")
+}
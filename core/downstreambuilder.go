@@ -8,6 +8,7 @@ import (
 
 	"github.com/anz-bank/sysl-go/common"
 	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/tracing"
 )
 
 func BuildDownstreamHTTPClient(ctx context.Context, serviceName string, hooks *Hooks, cfg *config.CommonDownstreamData) (client *http.Client, serviceURL string, err error) {
@@ -25,6 +26,14 @@ func BuildDownstreamHTTPClient(ctx context.Context, serviceName string, hooks *H
 	}
 
 	client.Transport = common.NewLoggingRoundTripper(serviceName, client.Transport)
+	client.Transport = tracing.NewRoundTripper(serviceName, client.Transport)
+	client.Transport = common.NewTraceIDRoundTripper(client.Transport)
+	if cfg != nil && cfg.Retry != nil {
+		client.Transport = common.NewRetryRoundTripper(*cfg.Retry, client.Transport)
+	}
+	if cfg != nil && cfg.CircuitBreaker != nil {
+		client.Transport = common.NewCircuitBreakerRoundTripper(serviceName, *cfg.CircuitBreaker, client.Transport)
+	}
 	if hooks != nil && hooks.DownstreamRoundTripper != nil {
 		client.Transport = hooks.DownstreamRoundTripper(serviceName, serviceURL, client.Transport)
 	}
@@ -40,5 +49,10 @@ func BuildDownstreamGRPCClient(ctx context.Context, serviceName string, hooks *H
 	if err != nil {
 		return nil, err
 	}
+	opts = append(opts, grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(serviceName), common.TraceIDUnaryClientInterceptor))
+	opts = append(opts, grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor(serviceName), common.TraceIDStreamClientInterceptor))
+	if cfg.CircuitBreaker != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(common.NewCircuitBreakerUnaryClientInterceptor(serviceName, *cfg.CircuitBreaker)))
+	}
 	return grpc.Dial(cfg.ServiceAddress, opts...)
 }
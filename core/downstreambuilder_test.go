@@ -1,9 +1,16 @@
 package core
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/tracing"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,3 +35,84 @@ func TestDownstreamRoundTripper(t *testing.T) {
 	require.NotNil(t, client)
 	require.IsType(t, roundTripper{}, client.Transport)
 }
+
+func TestDownstreamRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultCommonDownstreamData()
+	cfg.ServiceURL = server.URL
+	cfg.Retry = &config.RetryConfig{InitialBackoff: time.Millisecond}
+	client, serviceURL, err := BuildDownstreamHTTPClient(ctx, "Name", nil, cfg)
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 3, attempts)
+}
+
+func TestDownstreamCircuitBreaker(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultCommonDownstreamData()
+	cfg.ServiceURL = server.URL
+	cfg.CircuitBreaker = &config.CircuitBreakerConfig{FailureThreshold: 2}
+	client, serviceURL, err := BuildDownstreamHTTPClient(ctx, "Name", nil, cfg)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req) //nolint:bodyclose // No response is returned.
+	require.True(t, errors.Is(err, common.ErrCircuitOpen))
+	require.Equal(t, 2, attempts)
+}
+
+func TestDownstreamTraceID(t *testing.T) {
+	var requestID, traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("RequestID")
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultCommonDownstreamData()
+	cfg.ServiceURL = server.URL
+	client, serviceURL, err := BuildDownstreamHTTPClient(ctx, "Name", nil, cfg)
+	require.NoError(t, err)
+
+	id := uuid.New()
+	reqCtx, span := tracing.NewTracer(nil, nil).Start(common.AddTraceIDToContext(ctx, id, true), "span", tracing.SpanKindServer)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, serviceURL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, id.String(), requestID)
+	require.Contains(t, traceparent, span.SpanContext().TraceID.String())
+}
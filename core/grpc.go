@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/anz-bank/sysl-go/log"
 
+	"github.com/anz-bank/sysl-go/common"
 	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/handlerinitialiser"
+	"github.com/anz-bank/sysl-go/metrics"
+	"github.com/anz-bank/sysl-go/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // Deprecated: prefer GrpcServerManager.
@@ -39,8 +45,15 @@ func DefaultGrpcServerOptions(ctx context.Context, grpcPublicServerConfig *confi
 	logger := log.GetLogger(ctx)
 	// Inject the logger into the ctx so we can log when we're serving rpc calls.
 	opts = append(opts, grpc.ChainUnaryInterceptor(makeLoggerInterceptor(logger)))
+	opts = append(opts, grpc.ChainStreamInterceptor(makeLoggerStreamInterceptor(logger)))
 
+	tracer := tracing.GetTracer(ctx)
+	opts = append(opts, grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(tracer)))
+	opts = append(opts, grpc.ChainStreamInterceptor(makeTracingStreamInterceptor(tracer)))
 	opts = append(opts, grpc.ChainUnaryInterceptor(TraceidLogInterceptor))
+	opts = append(opts, grpc.ChainStreamInterceptor(TraceidLogStreamInterceptor))
+	opts = append(opts, grpc.ChainUnaryInterceptor(PeerIdentityInterceptor))
+	opts = append(opts, grpc.ChainStreamInterceptor(PeerIdentityStreamInterceptor))
 	return opts, nil
 }
 
@@ -63,12 +76,29 @@ func extractGrpcServerOptionsFromGrpcManager(ctx context.Context, hl GrpcManager
 	}
 	opts = append(opts, grpc.ChainUnaryInterceptor(hl.Interceptors()...))
 	opts = append(opts, grpc.ChainUnaryInterceptor(makeLoggerInterceptor(log.GetLogger(ctx))))
+	opts = append(opts, grpc.ChainStreamInterceptor(makeLoggerStreamInterceptor(log.GetLogger(ctx))))
+	opts = append(opts, grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(tracing.GetTracer(ctx))))
+	opts = append(opts, grpc.ChainStreamInterceptor(makeTracingStreamInterceptor(tracing.GetTracer(ctx))))
 	opts = append(opts, grpc.ChainUnaryInterceptor(TraceidLogInterceptor)) // seems wrong to have this last in chain, but that was old behaviour.
+	opts = append(opts, grpc.ChainStreamInterceptor(TraceidLogStreamInterceptor))
+	opts = append(opts, grpc.ChainUnaryInterceptor(PeerIdentityInterceptor))
+	opts = append(opts, grpc.ChainStreamInterceptor(PeerIdentityStreamInterceptor))
 	return opts, nil
 }
 
-func configurePublicGrpcServerListener(ctx context.Context, m GrpcServerManager) StoppableServer {
-	server := grpc.NewServer(m.GrpcServerOptions...)
+func configurePublicGrpcServerListener(ctx context.Context, m GrpcServerManager, promRegistry *prometheus.Registry) StoppableServer {
+	// Recover from panics and collect metrics outside of all other interceptors.
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(GrpcRecoveryInterceptor),
+		grpc.ChainStreamInterceptor(GrpcRecoveryStreamInterceptor),
+	}
+	if promRegistry != nil {
+		grpcMetrics := metrics.NewGRPCServerMetrics(promRegistry, appName(ctx))
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor()))
+	}
+	server := grpc.NewServer(append(opts, m.GrpcServerOptions...)...)
 	cfg := config.GetDefaultConfig(ctx)
 	if cfg != nil && cfg.GenCode.Upstream.GRPC.EnableReflection {
 		reflection.Register(server)
@@ -78,14 +108,20 @@ func configurePublicGrpcServerListener(ctx context.Context, m GrpcServerManager)
 		h.RegisterServer(ctx, server)
 	}
 
-	return prepareGrpcServerListener(ctx, server, *m.GrpcPublicServerConfig, "gRPC Public server")
+	return prepareGrpcServerListener(ctx, server, *m.GrpcPublicServerConfig, serverName(ctx, "gRPC Public server"))
 }
 
 type grpcServer struct {
-	ctx    context.Context
-	cfg    config.GRPCServerConfig
-	server *grpc.Server
-	name   string
+	ctx      context.Context
+	cfg      config.GRPCServerConfig
+	server   *grpc.Server
+	name     string
+	listener *boundListener
+}
+
+// Listen binds the listener of the server, which is then served by Start.
+func (s grpcServer) Listen() error {
+	return s.listener.listen(fmt.Sprintf("%s:%d", s.cfg.HostName, s.cfg.Port))
 }
 
 func (s grpcServer) Start() error {
@@ -94,7 +130,7 @@ func (s grpcServer) Start() error {
 	} else {
 		log.Infof(s.ctx, "TLS configuration NOT present. Preparing to serve gRPC/HTTP for address: %s:%d", s.cfg.HostName, s.cfg.Port)
 	}
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.cfg.HostName, s.cfg.Port))
+	lis, err := s.listener.get(fmt.Sprintf("%s:%d", s.cfg.HostName, s.cfg.Port))
 	if err != nil {
 		return err
 	}
@@ -102,11 +138,13 @@ func (s grpcServer) Start() error {
 }
 
 func (s grpcServer) GracefulStop() error {
+	defer s.listener.close()
 	s.server.GracefulStop()
 	return nil
 }
 
 func (s grpcServer) Stop() error {
+	defer s.listener.close()
 	s.server.Stop()
 	return nil
 }
@@ -142,7 +180,7 @@ func prepareGrpcServerListener(ctx context.Context, server *grpc.Server, commonC
 			&logWriterError{logger: logger}))
 
 	log.Infof(ctx, "configured gRPC listener for address: %s:%d", commonConfig.HostName, commonConfig.Port)
-	return grpcServer{ctx: ctx, cfg: commonConfig, server: server, name: name}
+	return grpcServer{ctx: ctx, cfg: commonConfig, server: server, name: name, listener: &boundListener{}}
 }
 
 func makeLoggerInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
@@ -152,6 +190,70 @@ func makeLoggerInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+func makeLoggerStreamInterceptor(logger log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, ServerStreamWithContext(log.PutLogger(ss.Context(), logger), ss))
+	}
+}
+
+// makeTracingStreamInterceptor returns a stream server interceptor that puts the given tracer in the
+// stream context and records a server span for each call, as tracing.UnaryServerInterceptor does
+// for unary calls.
+func makeTracingStreamInterceptor(tracer *tracing.Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := tracing.PutTracer(ss.Context(), tracer)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = tracer.Extract(ctx, tracing.MetadataCarrier(md))
+		}
+		ctx, span := tracer.Start(ctx, info.FullMethod, tracing.SpanKindServer)
+		defer span.End()
+		span.SetAttribute(tracing.AttributeRPCMethod, info.FullMethod)
+
+		err := handler(srv, ServerStreamWithContext(ctx, ss))
+		span.SetAttribute(tracing.AttributeRPCStatusCode, int(status.Code(err)))
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+// TraceidLogInterceptor records the trace ID of the current span (see tracing.UnaryServerInterceptor)
+// against the context and its logger, as common.TraceabilityMiddleware does for REST requests.
 func TraceidLogInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	return handler(log.WithStr(ctx, "traceid", "traceid"), req)
+	sc := tracing.SpanContextFromContext(ctx)
+	traceID := uuid.New()
+	if sc.TraceID.IsValid() {
+		traceID = uuid.UUID(sc.TraceID)
+	}
+	ctx = common.AddTraceIDToContext(ctx, traceID, sc.TraceID.IsValid())
+	return handler(log.WithStr(ctx, "traceid", traceID.String()), req)
+}
+
+// TraceidLogStreamInterceptor records the trace ID of the current span against the stream context
+// and its logger, as TraceidLogInterceptor does for unary calls.
+func TraceidLogStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	sc := tracing.SpanContextFromContext(ctx)
+	traceID := uuid.New()
+	if sc.TraceID.IsValid() {
+		traceID = uuid.UUID(sc.TraceID)
+	}
+	ctx = common.AddTraceIDToContext(ctx, traceID, sc.TraceID.IsValid())
+	return handler(srv, ServerStreamWithContext(log.WithStr(ctx, "traceid", traceID.String()), ss))
+}
+
+// ServerStreamWithContext returns a grpc.ServerStream that wraps the given stream but returns the
+// given context from Context. It allows stream interceptors to pass values to stream handlers.
+func ServerStreamWithContext(ctx context.Context, ss grpc.ServerStream) grpc.ServerStream {
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
 }
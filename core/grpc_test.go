@@ -7,13 +7,17 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/anz-bank/sysl-go/common"
 	"github.com/anz-bank/sysl-go/config"
 	test "github.com/anz-bank/sysl-go/core/testdata/proto"
 	"github.com/anz-bank/sysl-go/handlerinitialiser"
+	"github.com/anz-bank/sysl-go/log"
 	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/anz-bank/sysl-go/tracing"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 const testPort = 8888
@@ -196,7 +200,7 @@ func Test_libMakesCorrectHandlerCalls(t *testing.T) {
 	grpcServerManager, err := newGrpcServerManagerFromGrpcManager(ctx, manager)
 	require.NoError(t, err)
 
-	srv := configurePublicGrpcServerListener(ctx, *grpcServerManager)
+	srv := configurePublicGrpcServerListener(ctx, *grpcServerManager, nil)
 	require.NotNil(t, srv)
 
 	defer func() {
@@ -214,3 +218,59 @@ func Test_libMakesCorrectHandlerCalls(t *testing.T) {
 	require.True(t, manager.methodsCalled["GrpcPublicServerConfig"])
 	require.True(t, manager.reg.methodsCalled["RegisterServer"])
 }
+
+func TestTraceidLogInterceptor(t *testing.T) {
+	ctx, span := tracing.NewTracer(nil, nil).Start(testutil.NewTestContext(), "span", tracing.SpanKindServer)
+	_, err := TraceidLogInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		traceID, wasProvided := common.TryGetTraceIDFromContext(ctx)
+		require.True(t, wasProvided)
+		require.Equal(t, span.SpanContext().TraceID, tracing.TraceID(traceID))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	ctx := testutil.NewTestContext()
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(exporter, nil)
+	logger := testutil.NewTestLogger()
+	ss := &testServerStream{ctx: metadata.NewIncomingContext(ctx, metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))}
+
+	var handled bool
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		handled = true
+		log.Info(ss.Context(), "handled")
+		traceID, wasProvided := common.TryGetTraceIDFromContext(ss.Context())
+		require.True(t, wasProvided)
+		require.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", traceID.String())
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/Test/Stream", IsServerStream: true}
+	chain := func(interceptors ...grpc.StreamServerInterceptor) grpc.StreamHandler {
+		h := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], h
+			h = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return h
+	}
+
+	err := chain(makeLoggerStreamInterceptor(logger), makeTracingStreamInterceptor(tracer), TraceidLogStreamInterceptor)(nil, ss)
+	require.NoError(t, err)
+	require.True(t, handled)
+	require.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", logger.LastEntry().Fields["traceid"])
+	require.Len(t, exporter.Spans(), 1)
+	require.Equal(t, "/Test/Stream", exporter.Spans()[0].Name)
+}
@@ -55,10 +55,13 @@ func configureAdminServerListener(ctx context.Context, hl Manager, promRegistry
 
 	// Define meta-service endpoints:
 	statusService := status.Service{
-		BuildMetadata: buildMetadata,
+		BuildMetadata: appBuildMetadata(ctx),
 		Config:        hl.LibraryConfig(),
 		Services:      hl.EnabledHandlers(),
 	}
+	if authRules := getAuthorizationRules(ctx); authRules != nil {
+		statusService.AuthorizationRules = authRules.Rules()
+	}
 
 	adminRouter.Route("/-", func(r chi.Router) {
 		if hl.AddAdminHTTPMiddleware() != nil {
@@ -67,6 +70,11 @@ func configureAdminServerListener(ctx context.Context, hl Manager, promRegistry
 		r.Route("/status", func(r chi.Router) {
 			status.WireRoutes(r, &statusService)
 		})
+		if logLevels := getLogLevels(ctx); logLevels != nil {
+			r.Route("/loglevel", func(r chi.Router) {
+				wireLogLevelRoutes(r, logLevels)
+			})
+		}
 		if promRegistry != nil {
 			r.Route("/metrics", func(r chi.Router) {
 				r.Get("/", metrics.Handler(promRegistry).(http.HandlerFunc))
@@ -80,7 +88,7 @@ func configureAdminServerListener(ctx context.Context, hl Manager, promRegistry
 		}
 	})
 
-	listenAdmin := prepareServerListener(ctx, rootAdminRouter, adminTLSConfig, *hl.AdminServerConfig(), "REST Admin Server")
+	listenAdmin := prepareServerListener(ctx, rootAdminRouter, adminTLSConfig, *hl.AdminServerConfig(), serverName(ctx, "REST Admin Server"))
 
 	return listenAdmin, nil
 }
@@ -106,7 +114,7 @@ func configurePublicServerListener(ctx context.Context, hl Manager, mWare []func
 		prepareServerListenerFn = hooks.StoppableServerBuilder
 	}
 
-	listenPublic := prepareServerListenerFn(ctx, rootPublicRouter, publicTLSConfig, hl.PublicServerConfig().HTTP, "REST Public Server")
+	listenPublic := prepareServerListenerFn(ctx, rootPublicRouter, publicTLSConfig, hl.PublicServerConfig().HTTP, serverName(ctx, "REST Public Server"))
 
 	return listenPublic, nil
 }
@@ -138,16 +146,25 @@ type httpServer struct {
 	server              *http.Server
 	gracefulStopTimeout time.Duration
 	name                string
+	listener            *boundListener
+}
+
+// Listen binds the listener of the server, which is then served by Start.
+func (s httpServer) Listen() error {
+	return s.listener.listen(s.addr())
 }
 
 func (s httpServer) Start() error {
-	var err error
+	ln, err := s.listener.get(s.addr())
+	if err != nil {
+		return err
+	}
 	if s.cfg.Common.TLS != nil {
 		anzlog.Infof(s.ctx, "TLS configuration present. Preparing to serve HTTPS for address: %s:%d%s", s.cfg.Common.HostName, s.cfg.Common.Port, s.cfg.BasePath)
-		err = s.server.ListenAndServeTLS("", "")
+		err = s.server.ServeTLS(ln, "", "")
 	} else {
 		anzlog.Infof(s.ctx, "no TLS configuration present. Preparing to serve HTTP for address: %s:%d%s", s.cfg.Common.HostName, s.cfg.Common.Port, s.cfg.BasePath)
-		err = s.server.ListenAndServe()
+		err = s.server.Serve(ln)
 	}
 
 	if err != http.ErrServerClosed {
@@ -170,6 +187,7 @@ func (s httpServer) GracefulStop() error {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	defer s.listener.close()
 	err := s.server.Shutdown(ctx)
 	if err == context.DeadlineExceeded {
 		anzlog.Infof(s.ctx, "warning: GracefulStop timed out for HTTP server, hard-stopping HTTP server")
@@ -179,9 +197,21 @@ func (s httpServer) GracefulStop() error {
 }
 
 func (s httpServer) Stop() error {
+	defer s.listener.close()
 	return s.server.Close()
 }
 
+// addr returns the address to listen on, defaulting the port as http.Server.ListenAndServe does.
+func (s httpServer) addr() string {
+	if s.server.Addr != "" {
+		return s.server.Addr
+	}
+	if s.cfg.Common.TLS != nil {
+		return ":https"
+	}
+	return ":http"
+}
+
 func (s httpServer) GetName() string {
 	return s.name
 }
@@ -194,15 +224,21 @@ func prepareServerListener(ctx context.Context, rootRouter http.Handler, tlsConf
 	server := makeNewServer(ctx, rootRouter, tlsConfig, httpConfig, serverLogger)
 	anzlog.Infof(ctx, "configured listener for address: %s:%d%s", httpConfig.Common.HostName, httpConfig.Common.Port, httpConfig.BasePath)
 	return httpServer{
-		ctx:    ctx,
-		cfg:    httpConfig,
-		server: server,
-		name:   name,
+		ctx:      ctx,
+		cfg:      httpConfig,
+		server:   server,
+		name:     name,
+		listener: &boundListener{},
 	}
 }
 
 func makeNewServer(ctx context.Context, router http.Handler, tlsConfig *tls.Config, serverConfig config.CommonHTTPServerConfig, serverLogger *log.Logger) *http.Server {
 	listenAddr := fmt.Sprintf("%s:%d", serverConfig.Common.HostName, serverConfig.Common.Port)
+	if tlsConfig != nil && tlsConfig.GetConfigForClient != nil && len(tlsConfig.NextProtos) == 0 {
+		// ServeTLS offers HTTP/2 by adding its protocol to a clone of the config, but the config of
+		// each handshake is built from this one when the trusted cert pool is reloaded.
+		tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+	}
 	return &http.Server{
 		Addr:              listenAddr,
 		Handler:           router,
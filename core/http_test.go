@@ -3,8 +3,13 @@ package core
 import (
 	"context"
 	"crypto/tls"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -97,7 +102,7 @@ func Test_prepareMiddleware(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got := prepareMiddleware("server", tt.args.promRegistry, contextTimeout)
+			got := prepareMiddleware("server", tt.args.promRegistry, contextTimeout, nil)
 			assert.NotEmpty(t, got)
 		})
 	}
@@ -163,6 +168,45 @@ func Test_makeNewServer(t *testing.T) {
 	}
 }
 
+func TestHTTPServerNegotiatesHTTP2WithReloadingTrustedCertPool(t *testing.T) {
+	ctx := testutil.NewTestContext()
+	certServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certServer.Close()
+	caFilename := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFilename, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certServer.Certificate().Raw}), 0600))
+
+	cfg := &config.TLSConfig{
+		MinVersion:    newString("1.2"),
+		MaxVersion:    newString("1.3"),
+		ClientAuth:    newString("NoClientCert"),
+		Ciphers:       []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		Renegotiation: newString("RenegotiateNever"),
+		TrustedCertPool: &config.TrustedCertPoolConfig{
+			Mode:           newString(config.FILEMODE),
+			Encoding:       newString(config.PEM),
+			Path:           &caFilename,
+			ReloadInterval: time.Millisecond,
+		},
+	}
+	tlsConfig, err := config.MakeTLSConfig(ctx, cfg)
+	require.NoError(t, err)
+	tlsConfig.Certificates = certServer.TLS.Certificates
+
+	server := makeNewServer(ctx, http.NotFoundHandler(), tlsConfig, config.CommonHTTPServerConfig{}, nil)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.ServeTLS(listener, "", "") }()
+	defer server.Close()
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // Only the negotiated protocol is checked.
+		NextProtos:         []string{"h2", "http/1.1"},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, "h2", conn.ConnectionState().NegotiatedProtocol)
+}
+
 func Test_prepareServerListener(t *testing.T) {
 	ctx := testutil.NewTestContext()
 
@@ -489,7 +533,7 @@ func Test_configureAdminServerListener_Valid(t *testing.T) {
 		public: func() *config.UpstreamConfig { return &config.UpstreamConfig{ContextTimeout: contextTimeout} },
 	}
 
-	mWare := prepareMiddleware("test", nil, contextTimeout)
+	mWare := prepareMiddleware("test", nil, contextTimeout, nil)
 
 	srv, err := configureAdminServerListener(ctx, manager, nil, nil, mWare.admin)
 	require.NotNil(t, srv)
@@ -522,7 +566,7 @@ func Test_configureAdminServerListener_MissingLibraryConfig(t *testing.T) {
 		public: func() *config.UpstreamConfig { return &config.UpstreamConfig{ContextTimeout: contextTimeout} },
 	}
 
-	mWare := prepareMiddleware("test", nil, contextTimeout)
+	mWare := prepareMiddleware("test", nil, contextTimeout, nil)
 
 	srv, err := configureAdminServerListener(ctx, manager, nil, nil, mWare.admin)
 	require.Nil(t, srv)
@@ -552,7 +596,7 @@ func Test_configureAdminServerListener_MissingAdminConfig(t *testing.T) {
 		public: func() *config.UpstreamConfig { return &config.UpstreamConfig{ContextTimeout: contextTimeout} },
 	}
 
-	mWare := prepareMiddleware("test", nil, contextTimeout)
+	mWare := prepareMiddleware("test", nil, contextTimeout, nil)
 
 	srv, err := configureAdminServerListener(ctx, manager, nil, nil, mWare.admin)
 	require.Nil(t, srv)
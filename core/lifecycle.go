@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+)
+
+const defaultShutdownHookTimeout = 30 * time.Second
+
+// runStartupHooks calls each of the given hooks in order, stopping at the first error.
+func runStartupHooks(ctx context.Context, name string, hooks []func(ctx context.Context) error) error {
+	for i, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("%s hook %d of %d failed: %w", name, i+1, len(hooks), err)
+		}
+	}
+	return nil
+}
+
+// runShutdownHooks calls each of the given hooks in reverse order, collecting any errors. The hooks
+// share a context that is cancelled once the given timeout has elapsed.
+func runShutdownHooks(ctx context.Context, timeout time.Duration, hooks []func(ctx context.Context) error) []error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errs := make([]error, 0)
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("OnShutdown hook %d of %d failed: %w", i+1, len(hooks), err))
+		}
+	}
+	return errs
+}
+
+func (s *autogenServer) lifecycleConfig() config.LifecycleConfig {
+	cfg := config.GetDefaultConfig(s.ctx)
+	if cfg == nil {
+		return config.LifecycleConfig{}
+	}
+	return cfg.Library.Lifecycle
+}
+
+func (s *autogenServer) onStartHooks() []func(ctx context.Context) error {
+	if s.hooks == nil {
+		return nil
+	}
+	return s.hooks.OnStart
+}
+
+func (s *autogenServer) onReadyHooks() []func(ctx context.Context) error {
+	if s.hooks == nil {
+		return nil
+	}
+	return s.hooks.OnReady
+}
+
+// shutdownHooks runs the OnShutdown hooks, at most once over the lifetime of the server.
+func (s *autogenServer) shutdownHooks() []error {
+	var errs []error
+	s.shutdownOnce.Do(func() {
+		if s.hooks == nil || len(s.hooks.OnShutdown) == 0 {
+			return
+		}
+		timeout := s.lifecycleConfig().ShutdownHookTimeout
+		if timeout == 0 {
+			timeout = defaultShutdownHookTimeout
+		}
+		errs = runShutdownHooks(s.ctx, timeout, s.hooks.OnShutdown)
+	})
+	return errs
+}
+
+// listener is implemented by servers that can bind their listeners before they are started. Start
+// binds the listeners of all such servers before starting them, so that the OnReady hooks are only
+// called once the servers accept connections.
+type listener interface {
+	Listen() error
+}
+
+// boundListener holds the listener bound by the Listen method of a server, shared by the copies of
+// the server. A nil boundListener binds the listener when the server is started.
+type boundListener struct {
+	m  sync.Mutex
+	ln net.Listener
+}
+
+// listen binds a listener to the given address unless one is already bound.
+func (b *boundListener) listen(addr string) error {
+	if b == nil {
+		return nil
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.ln != nil {
+		return nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	b.ln = ln
+	return nil
+}
+
+// get returns the bound listener, binding one to the given address if Listen was not called.
+func (b *boundListener) get(addr string) (net.Listener, error) {
+	if b == nil {
+		return net.Listen("tcp", addr)
+	}
+	if err := b.listen(addr); err != nil {
+		return nil, err
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.ln, nil
+}
+
+// close closes the bound listener, which is not closed by stopping a server that was never
+// started. The error from closing a listener that the server has already closed is ignored.
+func (b *boundListener) close() {
+	if b == nil {
+		return
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.ln != nil {
+		_ = b.ln.Close()
+	}
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/handlerinitialiser"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleEvents struct {
+	m      sync.Mutex
+	events []string
+}
+
+func (e *lifecycleEvents) hook(name string, err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		e.m.Lock()
+		defer e.m.Unlock()
+		e.events = append(e.events, name)
+		return err
+	}
+}
+
+func (e *lifecycleEvents) get() []string {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return append([]string{}, e.events...)
+}
+
+func newLifecycleTestServer(hooks *Hooks, server StoppableServer) *autogenServer {
+	hooks.StoppableServerBuilder = func(context.Context, http.Handler, *tls.Config, config.CommonHTTPServerConfig, string) StoppableServer {
+		return server
+	}
+	manager := &restManagerImpl{
+		handlers: func() []handlerinitialiser.HandlerInitialiser { return []handlerinitialiser.HandlerInitialiser{} },
+		library:  func() *config.LibraryConfig { return &config.LibraryConfig{} },
+		admin:    func() *config.CommonHTTPServerConfig { return nil },
+		public:   func() *config.UpstreamConfig { return &config.UpstreamConfig{ContextTimeout: contextTimeout} },
+	}
+	return &autogenServer{
+		ctx:         testutil.NewTestContext(),
+		name:        "lifecycle-test",
+		restManager: manager,
+		hooks:       hooks,
+	}
+}
+
+func TestLifecycleHooks_Order(t *testing.T) {
+	events := &lifecycleEvents{}
+	ready := make(chan struct{})
+	hooks := &Hooks{
+		OnStart: []func(ctx context.Context) error{events.hook("start1", nil), events.hook("start2", nil)},
+		OnReady: []func(ctx context.Context) error{events.hook("ready", nil), func(ctx context.Context) error {
+			close(ready)
+			return nil
+		}},
+		OnShutdown: []func(ctx context.Context) error{events.hook("shutdown1", nil), events.hook("shutdown2", nil)},
+	}
+	server := newBlockingStoppableServer(false)
+	srv := newLifecycleTestServer(hooks, server)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+
+	<-ready
+	<-server.started
+	require.NoError(t, srv.GracefulStop())
+	require.NoError(t, <-errChan)
+	require.Equal(t, []string{"start1", "start2", "ready", "shutdown2", "shutdown1"}, events.get())
+
+	// The shutdown hooks are only run once.
+	require.NoError(t, srv.GracefulStop())
+	require.Equal(t, []string{"start1", "start2", "ready", "shutdown2", "shutdown1"}, events.get())
+}
+
+func TestLifecycleHooks_OnReadyAfterListening(t *testing.T) {
+	// Find a free port for the server to listen on.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+
+	// The OnReady hook can connect to the server.
+	ready := make(chan error, 1)
+	hooks := &Hooks{
+		OnReady: []func(ctx context.Context) error{func(ctx context.Context) error {
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/", port))
+			if err == nil {
+				resp.Body.Close()
+			}
+			ready <- err
+			return nil
+		}},
+	}
+	srv := newLifecycleTestServer(hooks, nil)
+	hooks.StoppableServerBuilder = func(ctx context.Context, h http.Handler, _ *tls.Config, _ config.CommonHTTPServerConfig, name string) StoppableServer {
+		cfg := config.CommonHTTPServerConfig{Common: config.CommonServerConfig{HostName: "localhost", Port: port}}
+		return prepareServerListener(ctx, h, nil, cfg, name)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+
+	require.NoError(t, <-ready)
+	require.NoError(t, srv.GracefulStop())
+	require.NoError(t, <-errChan)
+}
+
+func TestLifecycleHooks_OnStartErrorAbortsStart(t *testing.T) {
+	events := &lifecycleEvents{}
+	hooks := &Hooks{
+		OnStart:    []func(ctx context.Context) error{events.hook("start1", errors.New("boom")), events.hook("start2", nil)},
+		OnReady:    []func(ctx context.Context) error{events.hook("ready", nil)},
+		OnShutdown: []func(ctx context.Context) error{events.hook("shutdown", nil)},
+	}
+	srv := newLifecycleTestServer(hooks, newBlockingStoppableServer(false))
+
+	err := srv.Start()
+	require.EqualError(t, err, "OnStart hook 1 of 2 failed: boom")
+	require.Equal(t, []string{"start1"}, events.get())
+}
+
+func TestLifecycleHooks_OnReadyErrorStopsServer(t *testing.T) {
+	events := &lifecycleEvents{}
+	hooks := &Hooks{
+		OnReady:    []func(ctx context.Context) error{events.hook("ready", errors.New("boom"))},
+		OnShutdown: []func(ctx context.Context) error{events.hook("shutdown", nil)},
+	}
+	server := newBlockingStoppableServer(false)
+	srv := newLifecycleTestServer(hooks, server)
+
+	err := srv.Start()
+	require.EqualError(t, err, "OnReady hook 1 of 1 failed: boom")
+	_, stopCalled := server.calls()
+	require.True(t, stopCalled)
+	require.Equal(t, []string{"ready", "shutdown"}, events.get())
+}
+
+func TestLifecycleHooks_OnShutdownDeadlineAndErrors(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	hooks := &Hooks{
+		OnShutdown: []func(ctx context.Context) error{func(ctx context.Context) error {
+			deadline, hasDeadline = ctx.Deadline()
+			return errors.New("flush failed")
+		}},
+	}
+	server := newBlockingStoppableServer(false)
+	srv := newLifecycleTestServer(hooks, server)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+	<-server.started
+
+	err := srv.GracefulStop()
+	require.EqualError(t, err, "error during shutdown; sub-error(s): OnShutdown hook 1 of 1 failed: flush failed")
+	require.True(t, hasDeadline)
+	require.WithinDuration(t, time.Now().Add(defaultShutdownHookTimeout), deadline, 5*time.Second)
+	require.NoError(t, <-errChan)
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/go-chi/chi"
+)
+
+type logLevelsKey struct{}
+
+// withLogLevels puts the given log level switch in the context.
+func withLogLevels(ctx context.Context, levels *log.LevelSwitch) context.Context {
+	return context.WithValue(ctx, logLevelsKey{}, levels)
+}
+
+// getLogLevels returns the log level switch in the context, or nil.
+func getLogLevels(ctx context.Context) *log.LevelSwitch {
+	levels, _ := ctx.Value(logLevelsKey{}).(*log.LevelSwitch)
+	return levels
+}
+
+// logLevelResponse is the body of responses of the log level endpoint.
+type logLevelResponse struct {
+	Level     string            `json:"level"`
+	Overrides map[string]string `json:"overrides,omitempty"`
+	RevertAt  *time.Time        `json:"revert_at,omitempty"`
+}
+
+// logLevelRequest is the body of requests to set the log level. An unset level leaves the level
+// unchanged. Overrides set the levels of named loggers, replacing any previous overrides.
+// RevertAfter is a duration, e.g. "10m", after which the levels revert.
+type logLevelRequest struct {
+	Level       string            `json:"level"`
+	Overrides   map[string]string `json:"overrides"`
+	RevertAfter string            `json:"revert_after"`
+}
+
+// wireLogLevelRoutes adds the routes to read and set the log levels to the router.
+func wireLogLevelRoutes(r chi.Router, levels *log.LevelSwitch) {
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		writeLogLevels(w, levels)
+	})
+	r.Put("/", func(w http.ResponseWriter, r *http.Request) {
+		var req logLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		level, overrides, revertAfter, err := parseLogLevelRequest(req, levels.Level())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		levels.Set(level, overrides, revertAfter)
+		if revertAfter > 0 {
+			log.Infof(r.Context(), "log level set to %s with %d override(s), reverting after %s", level, len(overrides), revertAfter)
+		} else {
+			log.Infof(r.Context(), "log level set to %s with %d override(s)", level, len(overrides))
+		}
+		writeLogLevels(w, levels)
+	})
+}
+
+func parseLogLevelRequest(req logLevelRequest, current log.Level) (log.Level, map[string]log.Level, time.Duration, error) {
+	level := current
+	if req.Level != "" {
+		var err error
+		if level, err = log.ParseLevel(req.Level); err != nil {
+			return 0, nil, 0, err
+		}
+	}
+	overrides := make(map[string]log.Level, len(req.Overrides))
+	for name, value := range req.Overrides {
+		l, err := log.ParseLevel(value)
+		if err != nil {
+			return 0, nil, 0, fmt.Errorf("overrides.%s: %w", name, err)
+		}
+		overrides[name] = l
+	}
+	var revertAfter time.Duration
+	if req.RevertAfter != "" {
+		var err error
+		if revertAfter, err = time.ParseDuration(req.RevertAfter); err != nil || revertAfter <= 0 {
+			return 0, nil, 0, errors.New("revert_after must be a positive duration, e.g. 10m")
+		}
+	}
+	return level, overrides, revertAfter, nil
+}
+
+func writeLogLevels(w http.ResponseWriter, levels *log.LevelSwitch) {
+	resp := logLevelResponse{Level: levels.Level().String()}
+	if overrides := levels.Overrides(); len(overrides) > 0 {
+		resp.Overrides = make(map[string]string, len(overrides))
+		for name, level := range overrides {
+			resp.Overrides[name] = level.String()
+		}
+	}
+	if revertAt := levels.RevertAt(); !revertAt.IsZero() {
+		resp.RevertAt = &revertAt
+	}
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
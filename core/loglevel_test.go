@@ -0,0 +1,56 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+)
+
+func serveLogLevel(levels *log.LevelSwitch, method string, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	wireLogLevelRoutes(r, levels)
+	req := httptest.NewRequest(method, "/", strings.NewReader(body)).WithContext(testutil.NewTestContext())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogLevelRoutes(t *testing.T) {
+	levels := log.NewLevelSwitch(log.InfoLevel)
+
+	w := serveLogLevel(levels, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"level":"info"}`, w.Body.String())
+
+	w = serveLogLevel(levels, http.MethodPut, `{"overrides":{"common/internal/requestlogger.go":"debug"}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"level":"info","overrides":{"common/internal/requestlogger.go":"debug"}}`, w.Body.String())
+
+	w = serveLogLevel(levels, http.MethodPut, `{"level":"debug","revert_after":"10m"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"revert_at"`)
+	require.Equal(t, log.DebugLevel, levels.Level())
+	require.Empty(t, levels.Overrides())
+	levels.Set(log.InfoLevel, nil, 0)
+}
+
+func TestLogLevelRoutesInvalid(t *testing.T) {
+	levels := log.NewLevelSwitch(log.InfoLevel)
+	for _, body := range []string{
+		`{"level":"trace"}`,
+		`{"overrides":{"a":"verbose"}}`,
+		`{"level":"debug","revert_after":"soon"}`,
+		`{"level":"debug","revert_after":"-1m"}`,
+		`not json`,
+	} {
+		w := serveLogLevel(levels, http.MethodPut, body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	require.Equal(t, log.InfoLevel, levels.Level())
+}
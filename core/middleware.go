@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/anz-bank/sysl-go/common"
+	"github.com/anz-bank/sysl-go/config"
 	"github.com/anz-bank/sysl-go/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -15,13 +16,17 @@ type middlewareCollection struct {
 	public []func(handler http.Handler) http.Handler
 }
 
-func prepareMiddleware(name string, promRegistry *prometheus.Registry, contextTimeout time.Duration) middlewareCollection {
+func prepareMiddleware(name string, promRegistry *prometheus.Registry, contextTimeout time.Duration, accessLog *config.AccessLogConfig) middlewareCollection {
 	result := middlewareCollection{}
 	result.addToBoth(Recoverer)
 	result.addToBoth(common.Timeout(contextTimeout, http.HandlerFunc(timeoutHandler)))
 
 	result.public = append(result.public, common.TraceabilityMiddleware)
 	result.addToBoth(common.CoreRequestContextMiddleware)
+	if accessLog != nil {
+		result.addToBoth(common.AccessLogMiddleware(accessLog))
+	}
+	result.addToBoth(PeerIdentityMiddleware)
 
 	if promRegistry != nil {
 		metricsMiddleware := metrics.NewHTTPServerMetricsMiddleware(promRegistry, name, metrics.GetChiPathPattern)
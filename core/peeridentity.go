@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/anz-bank/sysl-go/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// PeerIdentityMiddleware records the identity of the verified client certificate of mutual TLS
+// requests against the request context (see common.PeerIdentityFromContext).
+func PeerIdentityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := common.PeerIdentityFromTLS(r.TLS); id != nil {
+			r = r.WithContext(common.PeerIdentityToContext(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PeerIdentityInterceptor records the identity of the verified client certificate of mutual TLS
+// calls against the context, as PeerIdentityMiddleware does for REST requests.
+func PeerIdentityInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(peerIdentityToContext(ctx), req)
+}
+
+// PeerIdentityStreamInterceptor records the identity of the verified client certificate of mutual
+// TLS streams against the stream context, as PeerIdentityInterceptor does for unary calls.
+func PeerIdentityStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	if withID := peerIdentityToContext(ctx); withID != ctx {
+		ss = ServerStreamWithContext(withID, ss)
+	}
+	return handler(srv, ss)
+}
+
+func peerIdentityToContext(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ctx
+	}
+	if id := common.PeerIdentityFromTLS(&tlsInfo.State); id != nil {
+		return common.PeerIdentityToContext(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func testVerifiedTLSState() *tls.ConnectionState {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "client"},
+		DNSNames: []string{"client.example.com"},
+	}
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+}
+
+func TestPeerIdentityMiddleware(t *testing.T) {
+	var id *common.PeerIdentity
+	handler := PeerIdentityMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		id = common.PeerIdentityFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+	r.TLS = testVerifiedTLSState()
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.NotNil(t, id)
+	require.Equal(t, "CN=client", id.Subject)
+	require.Equal(t, []string{"client.example.com"}, id.SANs())
+
+	r.TLS = &tls.ConnectionState{}
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.Nil(t, id)
+}
+
+func TestPeerIdentityInterceptor(t *testing.T) {
+	var id *common.PeerIdentity
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		id = common.PeerIdentityFromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: *testVerifiedTLSState()}})
+	_, err := PeerIdentityInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	require.Equal(t, "CN=client", id.Subject)
+
+	_, err = PeerIdentityInterceptor(peer.NewContext(context.Background(), &peer.Peer{}), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	require.Nil(t, id)
+}
@@ -1,10 +1,15 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"runtime/debug"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/anz-bank/sysl-go/log"
 )
 
@@ -12,19 +17,8 @@ func Recoverer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		defer func() {
-			var err error
 			if rvr := recover(); rvr != nil {
-				switch x := rvr.(type) {
-				case string:
-					err = errors.New(x)
-				case error:
-					err = x
-				default:
-					err = errors.New("unknown panic")
-				}
-				log.Errorf(ctx, err, "Panic: %+v\n", rvr)
-				log.Errorf(ctx, err, "%s", debug.Stack())
-
+				logPanic(ctx, rvr)
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
 		}()
@@ -32,3 +26,43 @@ func Recoverer(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// GrpcRecoveryInterceptor recovers from panics in unary gRPC handlers, logging the panic and
+// returning an Internal error to the client.
+func GrpcRecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logPanic(ctx, rvr)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// GrpcRecoveryStreamInterceptor recovers from panics in streaming gRPC handlers, as
+// GrpcRecoveryInterceptor does for unary handlers.
+func GrpcRecoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logPanic(ss.Context(), rvr)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func logPanic(ctx context.Context, rvr interface{}) {
+	var err error
+	switch x := rvr.(type) {
+	case string:
+		err = errors.New(x)
+	case error:
+		err = x
+	default:
+		err = errors.New("unknown panic")
+	}
+	log.Errorf(ctx, err, "Panic: %+v\n", rvr)
+	log.Errorf(ctx, err, "%s", debug.Stack())
+}
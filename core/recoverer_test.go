@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -11,6 +12,9 @@ import (
 
 	"github.com/anz-bank/sysl-go/testutil"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestRecoverer(t *testing.T) {
@@ -38,3 +42,21 @@ func loggerHookContextMiddleware() (func(next http.Handler) http.Handler, *testu
 		})
 	}, logger
 }
+
+func TestGrpcRecoveryInterceptor(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	_, err := GrpcRecoveryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		panic("Test")
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.NotZero(t, logger.EntryCount())
+}
+
+func TestGrpcRecoveryStreamInterceptor(t *testing.T) {
+	ctx, logger := testutil.NewTestContextWithLogger()
+	err := GrpcRecoveryStreamInterceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		panic("Test")
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.NotZero(t, logger.EntryCount())
+}
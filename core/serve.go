@@ -15,9 +15,12 @@ import (
 	pkgHealth "github.com/anz-bank/pkg/health"
 	pkg "github.com/anz-bank/pkg/log"
 	zero "github.com/anz-bank/pkg/logging"
+	"github.com/anz-bank/sysl-go/common"
 	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/core/authrules"
 	"github.com/anz-bank/sysl-go/health"
 	"github.com/anz-bank/sysl-go/log"
+	"github.com/anz-bank/sysl-go/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 )
@@ -26,7 +29,8 @@ type serveContextKey int
 
 const (
 	serveYAMLConfigFileKey serveContextKey = iota
-	defaultContextTimeout                  = 30 * time.Second
+	serveAppNameKey
+	defaultContextTimeout = 30 * time.Second
 )
 
 type ErrDisplayHelp int
@@ -43,6 +47,15 @@ func WithConfigFile(ctx context.Context, yamlConfigData []byte) context.Context
 	return context.WithValue(ctx, serveYAMLConfigFileKey, yamlConfigData)
 }
 
+// WithAppName adds the application name into the context. This name is
+// used as the service label of the Prometheus metrics, is logged against
+// every message, is reported by the status endpoint and is used to name
+// the servers. Generated code passes the name of the sysl application.
+// When no name is provided the link-time Name is used instead.
+func WithAppName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, serveAppNameKey, name)
+}
+
 // Serve is deprecated and will be removed once downstream applications cease
 // depending upon it. Generated code will no longer call this function.
 // This is a shim for compatibility with code generated by sysl-go versions v0.122.0 & earlier.
@@ -115,6 +128,16 @@ func NewServer(
 		},
 	}
 
+	if err := defaultConfig.Library.Authorization.Validate(); err != nil {
+		return nil, err
+	}
+	if err := defaultConfig.Library.Log.AccessLog.Validate(); err != nil {
+		return nil, err
+	}
+	if err := defaultConfig.Library.Log.PayloadRedaction.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Put the default configuration in the context.
 	ctx = config.PutDefaultConfig(ctx, defaultConfig)
 
@@ -158,22 +181,57 @@ func NewServer(
 	} else {
 		level = log.InfoLevel
 	}
-	ctx = log.WithLevel(ctx, level)
+	// The level can be changed at runtime through the admin server.
+	logLevels := log.NewLevelSwitch(level)
+	ctx = log.PutLogger(ctx, log.NewSwitchableLogger(log.GetLogger(ctx), logLevels))
+	ctx = withLogLevels(ctx, logLevels)
+
+	// Record the application name against the context and its logger.
+	name := appName(ctx)
+	ctx = WithAppName(ctx, name)
+	ctx = log.WithStr(ctx, "app", name)
+	logLevels.OnRevert(func(level log.Level) { log.Infof(ctx, "log level reverted to %s", level) })
+
+	// Set the tracer against the context, exporting spans to the exporter from the Hooks (if any).
+	propagator, err := tracing.ParsePropagators(defaultConfig.Library.Trace.Propagators)
+	if err != nil {
+		return nil, err
+	}
+	var exporter tracing.Exporter
+	if hooks != nil {
+		exporter = hooks.SpanExporter
+	}
+	ctx = tracing.PutTracer(ctx, tracing.NewTracer(exporter, propagator))
+
+	// Share the JWT authenticators between the authorization rules of all endpoints.
+	auths := newAuthenticators()
+	ctx = withAuthenticators(ctx, auths)
+
+	// Record the effective authorization rule of each endpoint.
+	authRules := newAuthorizationRules()
+	ctx = withAuthorizationRules(ctx, authRules)
 
 	// Collect prometheus metrics if the admin server is enabled.
 	var promRegistry *prometheus.Registry
 	if admin != nil {
 		promRegistry = prometheus.NewRegistry()
+		promRegistry.MustRegister(config.TLSReloadCollectors()...)
+		promRegistry.MustRegister(common.CircuitBreakerCollectors()...)
+		promRegistry.MustRegister(authrules.AuditCollectors()...)
+		promRegistry.MustRegister(auths)
 	}
 
 	manager, grpcManager, err := newManagers(ctx, serviceIntf, hooks)
 	if err != nil {
 		return nil, err
 	}
+	if err := authRules.checkConfigured(defaultConfig.Library.Authorization); err != nil {
+		return nil, err
+	}
 
 	server := &autogenServer{
 		ctx:                ctx,
-		name:               "nameless-autogenerated-app", // TODO source the application name from somewhere
+		name:               name,
 		restManager:        manager,
 		grpcServerManager:  grpcManager,
 		prometheusRegistry: promRegistry,
@@ -188,35 +246,42 @@ func NewServer(
 func LoadCustomConfig(ctx context.Context, customConfig interface{}) (interface{}, error) {
 	// Figure out where we can read application configuration data from.
 	var fs afero.Fs
-	var configPath string
+	var configPaths []string
 	if v := ctx.Value(serveYAMLConfigFileKey); v != nil {
 		applicationConfig := v.([]byte)
 		fs = afero.NewMemMapFs()
-		configPath = "config.yaml"
+		configPath := "config.yaml"
 		err := afero.Afero{Fs: fs}.WriteFile(configPath, applicationConfig, 0777)
 		if err != nil {
 			return nil, err
 		}
+		configPaths = []string{configPath}
 	} else {
 		fs = afero.NewOsFs()
-		if len(os.Args) != 2 {
-			return nil, fmt.Errorf("Wrong number of arguments (usage: %s (config | -h | --help | -v | --version))", os.Args[0])
+		args, err := parseConfigArgs(os.Args[0], os.Args[1:])
+		if err != nil {
+			return nil, err
 		}
-		switch os.Args[1] {
-		case "--help", "-h":
-			fmt.Printf("Usage: %s config\n\n", os.Args[0])
+		switch {
+		case args.help:
+			fmt.Printf("Usage: %s\n\n", usage(os.Args[0]))
+			fmt.Print("Config files are merged in order, with values in later files taking precedence.\n")
+			fmt.Print("Given --profile p, each config file dir/name.ext is followed by dir/name.p.ext if it exists.\n\n")
 			describeCustomConfig(os.Stdout, customConfig)
 			fmt.Print("\n\n")
 			return nil, ErrDisplayHelp(2)
-		case "--version", "-v":
-			fmt.Printf("%s\n", buildMetadata.String())
+		case args.version:
+			fmt.Printf("%s\n", appBuildMetadata(ctx).String())
 			return nil, ErrDisplayHelp(2)
 		}
-		configPath = os.Args[1]
+		configPaths, err = profileConfigFiles(fs, args.files, args.profile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Read application configuration data.
-	b := config.NewConfigReaderBuilder().WithFs(fs).WithConfigFile(configPath)
+	b := config.NewConfigReaderBuilder().WithFs(fs).WithConfigFiles(configPaths...)
 
 	envPrefixConfigKey := "envPrefix"
 
@@ -235,7 +300,21 @@ func LoadCustomConfig(ctx context.Context, customConfig interface{}) (interface{
 		b = b.AttachEnvPrefix(env)
 	}
 
-	err = b.Build().Unmarshal(customConfig)
+	reader := b.Build()
+	if readerSources, ok := reader.(config.ConfigSources); ok && len(configPaths) > 1 {
+		log.Infof(ctx, "merged configuration from %s", strings.Join(readerSources.Files(), ", "))
+		sources := readerSources.Sources()
+		keys := make([]string, 0, len(sources))
+		for key := range sources {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			log.Debugf(ctx, "config key %s read from %s", key, sources[key])
+		}
+	}
+
+	err = reader.Unmarshal(customConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -431,7 +510,9 @@ type autogenServer struct {
 	prometheusRegistry *prometheus.Registry
 	multiServer        StoppableServer
 	hooks              *Hooks
-	m                  sync.Mutex // protect access to multiServer
+	m                  sync.Mutex // protect access to multiServer and stopping
+	stopping           bool       // whether Stop or GracefulStop has been called
+	shutdownOnce       sync.Once  // run the OnShutdown hooks at most once
 }
 
 //nolint:funlen,gocognit
@@ -439,6 +520,11 @@ func (s *autogenServer) Start() error {
 	// precondition: ctx must have been threaded through InitialiseLogging and hence contain a logger
 	ctx := s.ctx
 
+	if err := runStartupHooks(ctx, "OnStart", s.onStartHooks()); err != nil {
+		log.Error(ctx, err, "error starting server")
+		return err
+	}
+
 	// prepare the middleware
 	var contextTimeout time.Duration
 	if s.restManager != nil && s.restManager.PublicServerConfig() != nil {
@@ -447,7 +533,11 @@ func (s *autogenServer) Start() error {
 	if contextTimeout == 0 {
 		contextTimeout = defaultContextTimeout
 	}
-	mWare := prepareMiddleware(s.name, s.prometheusRegistry, contextTimeout)
+	var accessLog *config.AccessLogConfig
+	if cfg := config.GetDefaultConfig(ctx); cfg != nil {
+		accessLog = cfg.Library.Log.AccessLog
+	}
+	mWare := prepareMiddleware(s.name, s.prometheusRegistry, contextTimeout, accessLog)
 
 	// load health server
 	var healthServer *health.Server = nil
@@ -496,7 +586,7 @@ func (s *autogenServer) Start() error {
 	// Make the listener function for the gRPC Public server.
 	if s.grpcServerManager != nil && s.grpcServerManager.GrpcPublicServerConfig != nil && len(s.grpcServerManager.EnabledGrpcHandlers) > 0 {
 		log.Info(ctx, "found GrpcPublicServerConfig for gRPC")
-		serverPublicGrpc := configurePublicGrpcServerListener(ctx, *s.grpcServerManager)
+		serverPublicGrpc := configurePublicGrpcServerListener(ctx, *s.grpcServerManager, s.prometheusRegistry)
 		servers = append(servers, serverPublicGrpc)
 		grpcIsRunning = true
 	} else {
@@ -510,19 +600,67 @@ func (s *autogenServer) Start() error {
 		panic(err)
 	}
 
-	s.newMultiStoppableServer(ctx, servers)
+	multiServer := s.newMultiStoppableServer(ctx, servers)
+
+	// Bind the listeners before starting the servers so that they accept connections by the time
+	// the OnReady hooks are called.
+	if err := multiServer.Listen(); err != nil {
+		log.Error(ctx, err, "error starting server")
+		if stopErr := s.Stop(); stopErr != nil {
+			log.Error(ctx, stopErr, "error stopping server")
+		}
+		return err
+	}
+
+	lifecycle := s.lifecycleConfig()
+	var signalChan <-chan os.Signal
+	if lifecycle.HandleSignals {
+		var stopNotify func()
+		signalChan, stopNotify = notifyShutdownSignals()
+		defer stopNotify()
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- multiServer.Start()
+	}()
+
+	if err := runStartupHooks(ctx, "OnReady", s.onReadyHooks()); err != nil {
+		log.Error(ctx, err, "error starting server")
+		if stopErr := s.Stop(); stopErr != nil {
+			log.Error(ctx, stopErr, "error stopping server")
+		}
+		return err
+	}
 
 	if healthServer != nil {
 		healthServer.SetReady(true)
 	}
 
-	return s.multiServer.Start()
+	err = awaitStop(ctx, s, errChan, signalChan, lifecycle, healthServer)
+	if !s.isStopping() {
+		// A server stopped by itself rather than through Stop or GracefulStop, so stop the other
+		// servers and run the OnShutdown hooks.
+		if stopErr := s.Stop(); stopErr != nil {
+			log.Error(ctx, stopErr, "error stopping server")
+		}
+	}
+	return err
 }
 
-func (s *autogenServer) newMultiStoppableServer(ctx context.Context, servers []StoppableServer) {
+func (s *autogenServer) newMultiStoppableServer(ctx context.Context, servers []StoppableServer) *multiStoppableServer {
 	s.m.Lock()
 	defer s.m.Unlock()
-	s.multiServer = &multiStoppableServer{ctx, servers}
+	multiServer := &multiStoppableServer{ctx, servers}
+	s.multiServer = multiServer
+	return multiServer
+}
+
+// isStopping reports whether Stop or GracefulStop has been called.
+func (s *autogenServer) isStopping() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.stopping
 }
 
 // FIXME replace MultiError with some existing type that does this job better.
@@ -540,25 +678,35 @@ func (e MultiError) Error() string {
 }
 
 func (s *autogenServer) Stop() error {
-	s.m.Lock()
-	defer s.m.Unlock()
-
-	if s.multiServer == nil {
-		return nil
-	}
-
-	return s.multiServer.Stop()
+	return s.stop(StoppableServer.Stop)
 }
 
 func (s *autogenServer) GracefulStop() error {
+	return s.stop(StoppableServer.GracefulStop)
+}
+
+// stop stops the servers using the given stop function and then runs the OnShutdown hooks.
+// The lock is only held while reading multiServer so that Stop can still be called to hard-stop
+// the servers while a GracefulStop is in progress.
+func (s *autogenServer) stop(stopFn func(StoppableServer) error) error {
 	s.m.Lock()
-	defer s.m.Unlock()
+	multiServer := s.multiServer
+	s.stopping = true
+	s.m.Unlock()
 
-	if s.multiServer == nil {
+	if multiServer == nil {
 		return nil
 	}
 
-	return s.multiServer.GracefulStop()
+	err := stopFn(multiServer)
+	hookErrs := s.shutdownHooks()
+	if len(hookErrs) == 0 {
+		return err
+	}
+	if err != nil {
+		hookErrs = append([]error{err}, hookErrs...)
+	}
+	return MultiError{Msg: "error during shutdown", Errors: hookErrs}
 }
 
 func (s *autogenServer) GetName() string {
@@ -574,6 +722,18 @@ func NewMultiStoppableServer(ctx context.Context, servers []StoppableServer) Sto
 	return &multiStoppableServer{ctx, servers}
 }
 
+// Listen binds the listeners of the servers that can bind them before being started.
+func (s *multiStoppableServer) Listen() error {
+	for i, server := range s.servers {
+		if l, ok := server.(listener); ok {
+			if err := l.Listen(); err != nil {
+				return fmt.Errorf("Server %d (%s) failed to listen: %v", i+1, server.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *multiStoppableServer) Start() error {
 	// precondition: ctx must have been threaded through InitialiseLogging and hence contain a logger
 	ctx := s.ctx
@@ -692,6 +852,10 @@ func getExternalLogger(ctx context.Context) (context.Context, log.Logger) {
 	if logger != nil {
 		return ctx, logger
 	}
+	if lgr := log.SlogLoggerFromContext(ctx); lgr != nil {
+		lgr.Debug("slog logger configuration detected")
+		return log.PutLogger(ctx, lgr), lgr
+	}
 	logrus := log.GetLogrusLoggerFromContext(ctx) // nolint:staticcheck
 	if logrus != nil {
 		lgr := log.NewLogrusLogger(logrus)
@@ -0,0 +1,32 @@
+//go:build go1.21
+// +build go1.21
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test a new server initialises a suitable logger if the slog logger is found in the context.
+func TestNewServerInitialisesLogger_externalSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := log.SlogLoggerToContext(context.Background(), slog.New(slog.NewTextHandler(buf, nil)))
+	ctx, err := newServerContextWithHooks(ctx, &Hooks{
+		Logger: func() log.Logger {
+			t.Fatal("hook should not be called")
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	logger := log.GetLogger(ctx)
+	assert.NotNil(t, logger)
+	logger.Info("hello")
+	assert.Contains(t, buf.String(), "hello")
+}
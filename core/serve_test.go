@@ -172,6 +172,39 @@ func TestNewServerInitialisesLogger_externalPkgLogger(t *testing.T) {
 	assert.Contains(t, buf.String(), "hello")
 }
 
+// Test a new server takes its name from the context and logs it against every message.
+func TestNewServerAppName(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := WithAppName(context.Background(), "TestApp")
+	ctx, err := newServerContextWithHooks(ctx, &Hooks{
+		Logger: func() log.Logger {
+			return log.NewPkgLogger(pkg.Fields{}.WithConfigs(pkg.SetOutput(buf)))
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "TestApp", appName(ctx))
+	assert.Equal(t, "TestApp REST Public Server", serverName(ctx, "REST Public Server"))
+	assert.Equal(t, "TestApp", appBuildMetadata(ctx).Name)
+	log.Info(ctx, "hello")
+	assert.Contains(t, buf.String(), "app=TestApp")
+}
+
+// Test a new server falls back to the link-time name when no name is given.
+func TestNewServerAppName_linkTime(t *testing.T) {
+	defer func(name string) { Name = name }(Name)
+
+	Name = ""
+	ctx, err := newServerContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, defaultAppName, appName(ctx))
+
+	Name = "LinkedApp"
+	ctx, err = newServerContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "LinkedApp", appName(ctx))
+}
+
 // newServerContext returns the context used against the server returned from NewServer.
 func newServerContext(ctx context.Context) (context.Context, error) {
 	return newServerContextWithHooks(ctx, nil)
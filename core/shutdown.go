@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/health"
+	"github.com/anz-bank/sysl-go/log"
+)
+
+// Exit codes returned by ExitCode.
+const (
+	ExitCodeSuccess         = 0
+	ExitCodeError           = 1
+	ExitCodeShutdownTimeout = 2
+)
+
+// shutdownSignals are the signals trapped when library.lifecycle.handleSignals is set.
+var shutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// ShutdownError is returned from Start when the server was asked to shut down by an OS signal
+// but did not stop cleanly.
+type ShutdownError struct {
+	Signal   os.Signal
+	TimedOut bool
+	Cause    error
+}
+
+func (e *ShutdownError) Error() string {
+	msg := fmt.Sprintf("graceful stop after signal %s failed", e.Signal)
+	if e.TimedOut {
+		msg = fmt.Sprintf("graceful stop after signal %s timed out", e.Signal)
+	}
+	if e.Cause != nil {
+		return fmt.Sprintf("%s; cause: %s", msg, e.Cause)
+	}
+	return msg
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Cause
+}
+
+// ExitCode returns the process exit code that describes the given error returned from Start:
+// ExitCodeSuccess for a clean stop, ExitCodeShutdownTimeout if the servers had to be stopped
+// un-gracefully after a signal, and ExitCodeError otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	var shutdownErr *ShutdownError
+	if errors.As(err, &shutdownErr) && shutdownErr.TimedOut {
+		return ExitCodeShutdownTimeout
+	}
+	return ExitCodeError
+}
+
+// HandlesSignals reports whether the given server traps OS signals itself (see
+// library.lifecycle.handleSignals), in which case the caller should not install its own
+// signal handling around Start.
+func HandlesSignals(srv StoppableServer) bool {
+	s, ok := srv.(*autogenServer)
+	return ok && s.lifecycleConfig().HandleSignals
+}
+
+// notifyShutdownSignals returns a channel that receives the shutdownSignals, along with a
+// function that stops the notifications.
+func notifyShutdownSignals() (<-chan os.Signal, func()) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, shutdownSignals...)
+	return signalChan, func() { signal.Stop(signalChan) }
+}
+
+// awaitStop blocks until either the started server stops by itself, as reported on errChan, or a
+// signal is received on signalChan (which may be nil). On receiving a signal the health server (if
+// any) is marked not-ready, the configured drain period is waited out and the server is gracefully
+// stopped, falling back to an un-graceful stop if the graceful stop exceeds the configured timeout.
+func awaitStop(ctx context.Context, server StoppableServer, errChan <-chan error, signalChan <-chan os.Signal, cfg config.LifecycleConfig, healthServer *health.Server) error {
+	select {
+	case err := <-errChan:
+		return err
+	case sig := <-signalChan:
+		log.Infof(ctx, "received signal %s, shutting down", sig)
+		err := shutdown(ctx, server, cfg, healthServer, sig)
+		log.Infof(ctx, "shutdown complete (exit code %d)", ExitCode(err))
+		return err
+	}
+}
+
+func shutdown(ctx context.Context, server StoppableServer, cfg config.LifecycleConfig, healthServer *health.Server, sig os.Signal) error {
+	if healthServer != nil {
+		healthServer.SetReady(false)
+	}
+
+	if cfg.DrainPeriod > 0 {
+		log.Infof(ctx, "draining for %s before stopping", cfg.DrainPeriod)
+		time.Sleep(cfg.DrainPeriod)
+	}
+
+	timeout := cfg.GracefulStopTimeout
+	if timeout == 0 {
+		timeout = defaultGracefulStopTimeout
+	}
+
+	// The channel is buffered so that the goroutine can exit if the graceful stop completes after
+	// the timeout, when nothing is left to receive from it.
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.GracefulStop()
+	}()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			return &ShutdownError{Signal: sig, Cause: err}
+		}
+		return nil
+	case <-time.After(timeout):
+		log.Warnf(ctx, "graceful stop did not complete within %s, hard-stopping", timeout)
+		return &ShutdownError{Signal: sig, TimedOut: true, Cause: server.Stop()}
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config"
+	"github.com/anz-bank/sysl-go/health"
+	"github.com/anz-bank/sysl-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingStoppableServer struct {
+	started      chan struct{}
+	stop         chan struct{}
+	graceful     chan struct{}
+	stopOnce     sync.Once
+	m            sync.Mutex // protect access to stopCalled and gracefulCall
+	blockOnStop  bool
+	stopCalled   bool
+	gracefulCall bool
+}
+
+func newBlockingStoppableServer(blockOnStop bool) *blockingStoppableServer {
+	return &blockingStoppableServer{
+		started:     make(chan struct{}),
+		stop:        make(chan struct{}),
+		graceful:    make(chan struct{}),
+		blockOnStop: blockOnStop,
+	}
+}
+
+func (s *blockingStoppableServer) Start() error {
+	close(s.started)
+	<-s.stop
+	return nil
+}
+
+func (s *blockingStoppableServer) Stop() error {
+	s.m.Lock()
+	s.stopCalled = true
+	s.m.Unlock()
+	s.stopOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (s *blockingStoppableServer) GracefulStop() error {
+	s.m.Lock()
+	s.gracefulCall = true
+	s.m.Unlock()
+	if s.blockOnStop {
+		<-s.graceful
+		return nil
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+// calls reports whether GracefulStop and Stop have been called.
+func (s *blockingStoppableServer) calls() (gracefulCall, stopCalled bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.gracefulCall, s.stopCalled
+}
+
+func (s *blockingStoppableServer) GetName() string {
+	return "blockingStoppableServer"
+}
+
+// newSignalTestServer returns a server that serves the given server and handles signals with the
+// given lifecycle configuration.
+func newSignalTestServer(hooks *Hooks, server StoppableServer, lifecycle config.LifecycleConfig) *autogenServer {
+	lifecycle.HandleSignals = true
+	srv := newLifecycleTestServer(hooks, server)
+	srv.ctx = config.PutDefaultConfig(srv.ctx, &config.DefaultConfig{Library: config.LibraryConfig{Lifecycle: lifecycle}})
+	return srv
+}
+
+func signalSelf(t *testing.T, sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(sig))
+}
+
+func TestStart_SignalGracefulStop(t *testing.T) {
+	events := &lifecycleEvents{}
+	hooks := &Hooks{OnShutdown: []func(ctx context.Context) error{events.hook("shutdown", nil)}}
+	server := newBlockingStoppableServer(false)
+	srv := newSignalTestServer(hooks, server, config.LifecycleConfig{DrainPeriod: 10 * time.Millisecond})
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+
+	<-server.started
+	signalSelf(t, syscall.SIGTERM)
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+		require.Equal(t, ExitCodeSuccess, ExitCode(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after signal")
+	}
+	gracefulCall, stopCalled := server.calls()
+	require.True(t, gracefulCall)
+	require.False(t, stopCalled)
+	require.Equal(t, []string{"shutdown"}, events.get())
+}
+
+func TestStart_SignalGracefulStopTimeout(t *testing.T) {
+	server := newBlockingStoppableServer(true)
+	defer close(server.graceful)
+	srv := newSignalTestServer(&Hooks{}, server, config.LifecycleConfig{GracefulStopTimeout: 10 * time.Millisecond})
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+
+	<-server.started
+	signalSelf(t, syscall.SIGINT)
+
+	select {
+	case err := <-errChan:
+		var shutdownErr *ShutdownError
+		require.True(t, errors.As(err, &shutdownErr))
+		require.True(t, shutdownErr.TimedOut)
+		require.Equal(t, syscall.SIGINT, shutdownErr.Signal)
+		require.Equal(t, ExitCodeShutdownTimeout, ExitCode(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after signal")
+	}
+	gracefulCall, stopCalled := server.calls()
+	require.True(t, gracefulCall)
+	require.True(t, stopCalled)
+}
+
+func TestStart_ServerStopsByItself(t *testing.T) {
+	events := &lifecycleEvents{}
+	hooks := &Hooks{OnShutdown: []func(ctx context.Context) error{events.hook("shutdown", nil)}}
+	server := &testStoppableServer{start: func() error { return errors.New("boom") }}
+	srv := newSignalTestServer(hooks, server, config.LifecycleConfig{})
+
+	err := srv.Start()
+	require.EqualError(t, err, "Server 1 (testStoppableServer) returned an error: boom")
+	require.Equal(t, ExitCodeError, ExitCode(err))
+	require.Equal(t, []string{"shutdown"}, events.get())
+}
+
+func TestShutdown_MarksHealthServerNotReady(t *testing.T) {
+	ctx := testutil.NewTestContext()
+	server := newBlockingStoppableServer(false)
+	healthServer, err := health.NewServer()
+	require.NoError(t, err)
+	healthServer.SetReady(true)
+
+	require.NoError(t, shutdown(ctx, server, config.LifecycleConfig{}, healthServer, syscall.SIGTERM))
+	require.False(t, healthServer.State.IsReady())
+}
+
+func TestHandlesSignals(t *testing.T) {
+	cfg := &config.DefaultConfig{}
+	srv := &autogenServer{ctx: config.PutDefaultConfig(context.Background(), cfg)}
+	require.False(t, HandlesSignals(srv))
+
+	cfg.Library.Lifecycle.HandleSignals = true
+	require.True(t, HandlesSignals(srv))
+
+	require.False(t, HandlesSignals(&testStoppableServer{}))
+}
@@ -0,0 +1,121 @@
+package credauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/anz-bank/sysl-go/config/sensitive"
+	"github.com/anz-bank/sysl-go/jwtauth"
+)
+
+// Defaults of an APIKeyConfig.
+const (
+	DefaultAPIKeyHeader = "X-API-Key"
+	DefaultAPIKeyIssuer = "apikey"
+)
+
+// APIKeyConfig configures the authentication of requests with API keys.
+//
+// Keys are sent in the Header (X-API-Key if not set, or the gRPC metadata of the same name) or, if
+// set, the QueryParam of REST requests. Only the SHA-256 hashes of the keys are configured.
+type APIKeyConfig struct {
+	Header     string `json:"header,omitempty"     yaml:"header,omitempty"     mapstructure:"header"`
+	QueryParam string `json:"queryParam,omitempty" yaml:"queryParam,omitempty" mapstructure:"queryParam"`
+	// Issuer is the iss claim of the principals of the keys (DefaultAPIKeyIssuer if not set).
+	Issuer string             `json:"issuer,omitempty"     yaml:"issuer,omitempty"     mapstructure:"issuer"`
+	Keys   []APIKeyCredential `json:"keys"                 yaml:"keys"                 mapstructure:"keys"`
+}
+
+// APIKeyCredential describes an API key and its principal.
+type APIKeyCredential struct {
+	// Name identifies the key, and is the sub claim of its principal.
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+	// Hash is the hex encoded SHA-256 hash of the key.
+	Hash *sensitive.String `json:"hash" yaml:"hash" mapstructure:"hash"`
+	// Scope is the scope claim of the principal: space-separated scopes, as checked by jwtHasScope.
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty" mapstructure:"scope"`
+	// Claims are further claims of the principal.
+	Claims map[string]interface{} `json:"claims,omitempty" yaml:"claims,omitempty" mapstructure:"claims"`
+}
+
+// APIKeyAuthenticator authenticates requests with API keys.
+type APIKeyAuthenticator struct {
+	header     string
+	queryParam string
+	issuer     string
+	keys       []apiKey
+}
+
+type apiKey struct {
+	hash   []byte
+	claims jwtauth.Claims
+}
+
+// NewAPIKeyAuthenticator constructs an APIKeyAuthenticator from config.
+func NewAPIKeyAuthenticator(c *APIKeyConfig) (*APIKeyAuthenticator, error) {
+	if c == nil || len(c.Keys) == 0 {
+		return nil, errors.New("credauth.APIKeyConfig: Must have at least one key")
+	}
+	a := &APIKeyAuthenticator{
+		header:     c.Header,
+		queryParam: c.QueryParam,
+		issuer:     c.Issuer,
+	}
+	if a.header == "" {
+		a.header = DefaultAPIKeyHeader
+	}
+	if a.issuer == "" {
+		a.issuer = DefaultAPIKeyIssuer
+	}
+	names := map[string]bool{}
+	for _, k := range c.Keys {
+		if k.Name == "" {
+			return nil, errors.New("credauth.APIKeyConfig: Key must have a name")
+		}
+		if names[k.Name] {
+			return nil, errors.New("credauth.APIKeyConfig: Key names are not unique")
+		}
+		names[k.Name] = true
+		if k.Hash == nil {
+			return nil, fmt.Errorf("credauth.APIKeyConfig: Key %s must have a hash", k.Name)
+		}
+		hash, err := hex.DecodeString(k.Hash.Value())
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("credauth.APIKeyConfig: Key %s hash must be a hex encoded SHA-256 hash", k.Name)
+		}
+		a.keys = append(a.keys, apiKey{
+			hash:   hash,
+			claims: principalClaims(k.Claims, a.issuer, k.Name, k.Scope),
+		})
+	}
+	return a, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *APIKeyAuthenticator) Authenticate(_ context.Context, header http.Header, query url.Values) (jwtauth.Claims, error) {
+	key := header.Get(a.header)
+	if key == "" && a.queryParam != "" {
+		key = query.Get(a.queryParam)
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	hash := sha256.Sum256([]byte(key))
+	// Compare against every key so the time taken does not reveal which key matched.
+	var match *apiKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], a.keys[i].hash) == 1 {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, invalidCredentials(errors.New("unknown API key"))
+	}
+	return copyClaims(match.claims), nil
+}
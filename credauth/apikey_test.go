@@ -0,0 +1,91 @@
+package credauth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/config/sensitive"
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/stretchr/testify/require"
+)
+
+// keyHash is the SHA-256 hash of "key-123".
+const keyHash = "65803be0872fa538d3ac513edadd6699f54dd8b4a566ee1cf6b185c8cc803949"
+
+func newSecret(s string) *sensitive.String {
+	secret := sensitive.NewString(s)
+	return &secret
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	auth, err := credauth.NewAPIKeyAuthenticator(&credauth.APIKeyConfig{
+		QueryParam: "api_key",
+		Keys: []credauth.APIKeyCredential{{
+			Name:   "partner-a",
+			Hash:   newSecret(keyHash),
+			Scope:  "read write",
+			Claims: map[string]interface{}{"tenant": "t1"},
+		}},
+	})
+	require.NoError(t, err)
+
+	expected := jwtauth.Claims{"iss": "apikey", "sub": "partner-a", "scope": "read write", "tenant": "t1"}
+	claims, err := auth.Authenticate(context.Background(), http.Header{"X-Api-Key": {"key-123"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, claims)
+
+	claims, err = auth.Authenticate(context.Background(), http.Header{}, url.Values{"api_key": {"key-123"}})
+	require.NoError(t, err)
+	require.Equal(t, expected, claims)
+
+	// The claims of the principal cannot be modified by callers.
+	claims["sub"] = "someone-else"
+	claims, err = auth.Authenticate(context.Background(), http.Header{"X-Api-Key": {"key-123"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "partner-a", claims["sub"])
+
+	_, err = auth.Authenticate(context.Background(), http.Header{"X-Api-Key": {"key-456"}}, nil)
+	var authErr *jwtauth.AuthError
+	require.True(t, errors.As(err, &authErr))
+	require.Equal(t, jwtauth.AuthErrCodeInvalidCredentials, authErr.Code)
+
+	_, err = auth.Authenticate(context.Background(), http.Header{"Authorization": {"Bearer token"}}, nil)
+	require.Equal(t, credauth.ErrNoCredentials, err)
+}
+
+func TestAPIKeyAuthenticatorCustomHeader(t *testing.T) {
+	auth, err := credauth.NewAPIKeyAuthenticator(&credauth.APIKeyConfig{
+		Header: "X-Partner-Key",
+		Issuer: "partners",
+		Keys:   []credauth.APIKeyCredential{{Name: "partner-a", Hash: newSecret(keyHash)}},
+	})
+	require.NoError(t, err)
+
+	claims, err := auth.Authenticate(context.Background(), http.Header{"X-Partner-Key": {"key-123"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, jwtauth.Claims{"iss": "partners", "sub": "partner-a"}, claims)
+
+	// Keys are not accepted in the query unless configured.
+	_, err = auth.Authenticate(context.Background(), http.Header{}, url.Values{"api_key": {"key-123"}})
+	require.Equal(t, credauth.ErrNoCredentials, err)
+}
+
+func TestNewAPIKeyAuthenticatorErrors(t *testing.T) {
+	for _, tt := range []struct {
+		cfg *credauth.APIKeyConfig
+		err string
+	}{
+		{&credauth.APIKeyConfig{}, "credauth.APIKeyConfig: Must have at least one key"},
+		{&credauth.APIKeyConfig{Keys: []credauth.APIKeyCredential{{Hash: newSecret(keyHash)}}}, "credauth.APIKeyConfig: Key must have a name"},
+		{&credauth.APIKeyConfig{Keys: []credauth.APIKeyCredential{{Name: "a"}}}, "credauth.APIKeyConfig: Key a must have a hash"},
+		{&credauth.APIKeyConfig{Keys: []credauth.APIKeyCredential{{Name: "a", Hash: newSecret("key-123")}}}, "credauth.APIKeyConfig: Key a hash must be a hex encoded SHA-256 hash"},
+		{&credauth.APIKeyConfig{Keys: []credauth.APIKeyCredential{{Name: "a", Hash: newSecret(keyHash)}, {Name: "a", Hash: newSecret(keyHash)}}}, "credauth.APIKeyConfig: Key names are not unique"},
+	} {
+		_, err := credauth.NewAPIKeyAuthenticator(tt.cfg)
+		require.EqualError(t, err, tt.err)
+	}
+}
@@ -0,0 +1,64 @@
+package credauth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anz-bank/sysl-go/credauth"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/stretchr/testify/require"
+)
+
+// passwordHash is a bcrypt hash of "s3cret".
+const passwordHash = "$2a$04$UI3XFl7w3/wH57OpP.AuxOomf6Lc/2GlHNIR5KFV9Py3qwBUORok2"
+
+func basicHeader(username, password string) http.Header {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.SetBasicAuth(username, password)
+	return r.Header
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	auth, err := credauth.NewBasicAuthenticator(&credauth.BasicConfig{
+		Users: []credauth.BasicCredential{{
+			Username:     "alice",
+			PasswordHash: newSecret(passwordHash),
+			Scope:        "read",
+		}},
+	})
+	require.NoError(t, err)
+
+	claims, err := auth.Authenticate(context.Background(), basicHeader("alice", "s3cret"), nil)
+	require.NoError(t, err)
+	require.Equal(t, jwtauth.Claims{"iss": "basic", "sub": "alice", "scope": "read"}, claims)
+
+	for _, header := range []http.Header{basicHeader("alice", "wrong"), basicHeader("bob", "s3cret")} {
+		_, err = auth.Authenticate(context.Background(), header, nil)
+		var authErr *jwtauth.AuthError
+		require.True(t, errors.As(err, &authErr))
+		require.Equal(t, jwtauth.AuthErrCodeInvalidCredentials, authErr.Code)
+	}
+
+	_, err = auth.Authenticate(context.Background(), http.Header{"Authorization": {"Bearer token"}}, nil)
+	require.Equal(t, credauth.ErrNoCredentials, err)
+	_, err = auth.Authenticate(context.Background(), http.Header{}, nil)
+	require.Equal(t, credauth.ErrNoCredentials, err)
+}
+
+func TestNewBasicAuthenticatorErrors(t *testing.T) {
+	for _, tt := range []struct {
+		cfg *credauth.BasicConfig
+		err string
+	}{
+		{&credauth.BasicConfig{}, "credauth.BasicConfig: Must have at least one user"},
+		{&credauth.BasicConfig{Users: []credauth.BasicCredential{{PasswordHash: newSecret(passwordHash)}}}, "credauth.BasicConfig: User must have a username"},
+		{&credauth.BasicConfig{Users: []credauth.BasicCredential{{Username: "a"}}}, "credauth.BasicConfig: User a must have a passwordHash"},
+		{&credauth.BasicConfig{Users: []credauth.BasicCredential{{Username: "a", PasswordHash: newSecret("s3cret")}}}, "credauth.BasicConfig: User a passwordHash must be a bcrypt hash"},
+		{&credauth.BasicConfig{Users: []credauth.BasicCredential{{Username: "a", PasswordHash: newSecret(passwordHash)}, {Username: "a", PasswordHash: newSecret(passwordHash)}}}, "credauth.BasicConfig: Usernames are not unique"},
+	} {
+		_, err := credauth.NewBasicAuthenticator(tt.cfg)
+		require.EqualError(t, err, tt.err)
+	}
+}
@@ -0,0 +1,57 @@
+// Package credauth authenticates requests made with API keys or HTTP Basic credentials.
+//
+// Authenticated principals are described by jwtauth.Claims, with the name of the key or user as the
+// sub claim, so that the same authorization rule expressions apply to them as to JWTs.
+package credauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/anz-bank/sysl-go/jwtauth"
+)
+
+// ErrNoCredentials is returned by an Authenticator when a request has no credentials of its scheme.
+var ErrNoCredentials = errors.New("credauth: no credentials")
+
+// Authenticator authenticates the credentials of a request.
+type Authenticator interface {
+	// Authenticate returns the claims of the principal identified by the credentials in the given
+	// request header (or gRPC metadata) and query. It returns ErrNoCredentials if the request has
+	// no credentials of the scheme of the Authenticator.
+	Authenticate(ctx context.Context, header http.Header, query url.Values) (jwtauth.Claims, error)
+}
+
+// principalClaims returns the claims of a principal: the configured claims, with the given
+// issuer, subject and scope (if not empty).
+func principalClaims(claims map[string]interface{}, issuer, subject, scope string) jwtauth.Claims {
+	c := make(jwtauth.Claims, len(claims)+3)
+	for k, v := range claims {
+		c[k] = v
+	}
+	c["iss"] = issuer
+	c["sub"] = subject
+	if scope != "" {
+		c["scope"] = scope
+	}
+	return c
+}
+
+// copyClaims returns a shallow copy of the claims, so that callers cannot modify the claims of a
+// principal.
+func copyClaims(c jwtauth.Claims) jwtauth.Claims {
+	claims := make(jwtauth.Claims, len(c))
+	for k, v := range c {
+		claims[k] = v
+	}
+	return claims
+}
+
+func invalidCredentials(cause error) error {
+	return &jwtauth.AuthError{
+		Code:  jwtauth.AuthErrCodeInvalidCredentials,
+		Cause: cause,
+	}
+}
@@ -28,6 +28,9 @@ type Verifier interface {
 // issuers and keys.
 type StdAuthenticator struct {
 	Verifiers map[string]Verifier
+	// Validations holds the validation of the tokens of each issuer, keyed by issuer name. Tokens
+	// of issuers without one are validated with DefaultValidation.
+	Validations map[string]Validation
 }
 
 // Authenticate authenticates a jwt and returns the extracted claims, or an
@@ -51,13 +54,6 @@ func (a *StdAuthenticator) Authenticate(ctx context.Context, raw string) (Claims
 			Cause: errors.Wrap(err, "jwt verify error"),
 		}
 	}
-	if err := insecureClaims.ValidateWithLeeway(jwt.Expected{Time: time.Now()}, time.Second); err != nil {
-		pkgLogger.Debug(ctx, "jwt expired")
-		return Claims{}, &AuthError{
-			Code:  AuthErrCodeInvalidJWT,
-			Cause: err,
-		}
-	}
 	verifier, ok := a.Verifiers[insecureClaims.Issuer]
 	if !ok {
 		pkgLogger.Debugf(ctx, "issuer not registered: %s", insecureClaims.Issuer)
@@ -66,6 +62,19 @@ func (a *StdAuthenticator) Authenticate(ctx context.Context, raw string) (Claims
 			Cause: fmt.Errorf("issuer not registered: %s", insecureClaims.Issuer),
 		}
 	}
+	validation, ok := a.Validations[insecureClaims.Issuer]
+	if !ok {
+		validation = DefaultValidation
+	}
+	if err := validation.checkAlgorithm(token); err != nil {
+		pkgLogger.Debug(ctx, err)
+		return Claims{}, err
+	}
+	// Check the time claims before the signature, to avoid fetching keys for stale tokens.
+	if err := validation.checkTime(insecureClaims, time.Now()); err != nil {
+		pkgLogger.Debug(ctx, "jwt time validation failed:", err)
+		return Claims{}, err
+	}
 
 	// Verify the token and populate claims
 	var claims Claims
@@ -73,9 +82,25 @@ func (a *StdAuthenticator) Authenticate(ctx context.Context, raw string) (Claims
 		pkgLogger.Debug(ctx, err)
 		return Claims{}, err // Don't wrap this error
 	}
+	if err := validation.checkClaims(claims); err != nil {
+		pkgLogger.Debug(ctx, err)
+		return Claims{}, err
+	}
 	return claims, nil
 }
 
+// CacheStats returns the cache stats of each verifier that implements CacheStatsProvider, keyed by
+// issuer name.
+func (a *StdAuthenticator) CacheStats() map[string]CacheStats {
+	stats := map[string]CacheStats{}
+	for name, v := range a.Verifiers {
+		if p, ok := v.(CacheStatsProvider); ok {
+			stats[name] = p.CacheStats()
+		}
+	}
+	return stats
+}
+
 // InsecureAuthenticator does not attempt to verify the signature of a jwt.
 //
 // USE ONLY IN TESTING.
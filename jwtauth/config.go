@@ -2,9 +2,11 @@ package jwtauth
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"time"
 
+	"github.com/anz-bank/sysl-go/config/sensitive"
 	"github.com/anz-bank/sysl-go/jsontime"
 	"github.com/pkg/errors"
 )
@@ -24,6 +26,7 @@ func AuthFromConfig(ctx context.Context, c *Config, client func(string) *http.Cl
 		return nil, errors.New("AuthConfig: Config must not be nil")
 	}
 	verifiers := map[string]Verifier{}
+	validations := map[string]Validation{}
 	for _, ic := range c.Issuers {
 		if ic.Name == "" {
 			return nil, errors.New("AuthConfig: Issuer must have a name")
@@ -36,24 +39,97 @@ func AuthFromConfig(ctx context.Context, c *Config, client func(string) *http.Cl
 			return nil, errors.Wrapf(err, "AuthConfig: Error creating verifier for issuer %s", ic.Name)
 		}
 		verifiers[ic.Name] = v
+		validation, err := ValidationFromIssuerConfig(ic)
+		if err != nil {
+			return nil, errors.Wrapf(err, "AuthConfig: Error in validation config for issuer %s", ic.Name)
+		}
+		validations[ic.Name] = validation
 	}
 	return &StdAuthenticator{
-		Verifiers: verifiers,
+		Verifiers:   verifiers,
+		Validations: validations,
 	}, nil
 }
 
 // IssuerConfig defines config for issuers for the std authenticator.
+//
+// Exactly one source of keys must be set: a remote jwks (JWKSURL), an OpenID Connect issuer whose
+// jwks is discovered from its metadata (OIDCIssuerURL), a PEM encoded public key (PublicKey or
+// PublicKeyFile) or an HMAC shared secret (SharedSecret). The cache settings apply to remote keys only.
+//
+// Tokens are further validated against the expected audiences (any one of which must be in the aud
+// claim), the required claims, the leeway allowed for clock skew (DefaultLeeway if zero) and the
+// accepted signing algorithms (HMACAlgorithms for a shared secret, AsymmetricAlgorithms otherwise,
+// if not set).
 type IssuerConfig struct {
-	Name         string            `json:"name"                       yaml:"name"                       mapstructure:"name"`
-	JWKSURL      string            `json:"jwksUrl,omitempty"          yaml:"jwksUrl,omitempty"          mapstructure:"jwksUrl"`
-	CacheTTL     jsontime.Duration `json:"cacheTTL"                   yaml:"cacheTTL"                   mapstructure:"cacheTTL"`
-	CacheRefresh jsontime.Duration `json:"cacheRefresh"               yaml:"cacheRefresh"               mapstructure:"cacheRefresh"`
+	Name          string            `json:"name"                       yaml:"name"                       mapstructure:"name"`
+	JWKSURL       string            `json:"jwksUrl,omitempty"          yaml:"jwksUrl,omitempty"          mapstructure:"jwksUrl"`
+	OIDCIssuerURL string            `json:"oidcIssuerUrl,omitempty"    yaml:"oidcIssuerUrl,omitempty"    mapstructure:"oidcIssuerUrl"`
+	PublicKey     string            `json:"publicKey,omitempty"        yaml:"publicKey,omitempty"        mapstructure:"publicKey"`
+	PublicKeyFile string            `json:"publicKeyFile,omitempty"    yaml:"publicKeyFile,omitempty"    mapstructure:"publicKeyFile"`
+	SharedSecret  *sensitive.String `json:"sharedSecret,omitempty"     yaml:"sharedSecret,omitempty"     mapstructure:"sharedSecret"`
+	CacheTTL      jsontime.Duration `json:"cacheTTL"                   yaml:"cacheTTL"                   mapstructure:"cacheTTL"`
+	CacheRefresh  jsontime.Duration `json:"cacheRefresh"               yaml:"cacheRefresh"               mapstructure:"cacheRefresh"`
+
+	Audiences      []string          `json:"audiences,omitempty"        yaml:"audiences,omitempty"        mapstructure:"audiences"`
+	RequiredClaims []string          `json:"requiredClaims,omitempty"   yaml:"requiredClaims,omitempty"   mapstructure:"requiredClaims"`
+	Leeway         jsontime.Duration `json:"leeway,omitempty"           yaml:"leeway,omitempty"           mapstructure:"leeway"`
+	Algorithms     []string          `json:"algorithms,omitempty"       yaml:"algorithms,omitempty"       mapstructure:"algorithms"`
+}
+
+// ValidationFromIssuerConfig creates the token validation of an issuer from issuer config.
+func ValidationFromIssuerConfig(i IssuerConfig) (Validation, error) {
+	hmac := i.hasSharedSecret()
+	algorithms := i.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = AsymmetricAlgorithms
+		if hmac {
+			algorithms = HMACAlgorithms
+		}
+	} else if err := validateAlgorithms(algorithms, hmac); err != nil {
+		return Validation{}, err
+	}
+	leeway := time.Duration(i.Leeway)
+	if leeway == 0 {
+		leeway = DefaultLeeway
+	}
+	return Validation{
+		Audiences:      i.Audiences,
+		RequiredClaims: i.RequiredClaims,
+		Leeway:         leeway,
+		Algorithms:     algorithms,
+	}, nil
+}
+
+func (i IssuerConfig) hasSharedSecret() bool {
+	return i.SharedSecret != nil && i.SharedSecret.Value() != ""
 }
 
 // VerifierFromIssuerConfig creates a token verifier from issuer config.
 func VerifierFromIssuerConfig(ctx context.Context, i IssuerConfig, client *http.Client) (Verifier, error) {
-	if i.JWKSURL != "" {
+	sources := 0
+	for _, set := range []bool{i.JWKSURL != "", i.OIDCIssuerURL != "", i.PublicKey != "", i.PublicKeyFile != "", i.hasSharedSecret()} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, errors.New("jwtauth.Config: Must have exactly one of jwksUrl, oidcIssuerUrl, publicKey, publicKeyFile or sharedSecret set")
+	}
+	switch {
+	case i.JWKSURL != "":
 		return NewRemoteJWKSIssuer(ctx, i.Name, i.JWKSURL, client, time.Duration(i.CacheTTL), time.Duration(i.CacheRefresh))
+	case i.OIDCIssuerURL != "":
+		return NewOIDCIssuer(ctx, i.Name, i.OIDCIssuerURL, client, time.Duration(i.CacheTTL), time.Duration(i.CacheRefresh))
+	case i.PublicKey != "":
+		return NewPublicKeyVerifier([]byte(i.PublicKey))
+	case i.PublicKeyFile != "":
+		key, err := ioutil.ReadFile(i.PublicKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "jwtauth.Config: Error reading publicKeyFile")
+		}
+		return NewPublicKeyVerifier(key)
+	default:
+		return NewSharedSecretVerifier([]byte(i.SharedSecret.Value()))
 	}
-	return nil, errors.New("jwtauth.Config: Can only have one of SharedSecret, PublicKey or JWKSURL set")
 }
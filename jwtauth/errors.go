@@ -38,6 +38,12 @@ const (
 	AuthErrCodeUntrustedSource
 	AuthErrCodeBadSignature
 	AuthErrCodeInsufficientPermissions
+	AuthErrCodeExpired
+	AuthErrCodeNotYetValid
+	AuthErrCodeInvalidAudience
+	AuthErrCodeMissingClaim
+	AuthErrCodeDisallowedAlgorithm
+	AuthErrCodeInvalidCredentials
 )
 
 var errHTTPCodeMap = map[int]int{
@@ -54,4 +60,22 @@ var errHTTPCodeMap = map[int]int{
 
 	// Request is authenticated but does not have sufficient permissions to execute.
 	AuthErrCodeInsufficientPermissions: http.StatusForbidden,
+
+	// Request jwt has expired.
+	AuthErrCodeExpired: http.StatusUnauthorized,
+
+	// Request jwt is not valid yet (nbf or iat in the future).
+	AuthErrCodeNotYetValid: http.StatusUnauthorized,
+
+	// Request jwt was not issued for us.
+	AuthErrCodeInvalidAudience: http.StatusUnauthorized,
+
+	// Request jwt lacks a claim we require.
+	AuthErrCodeMissingClaim: http.StatusUnauthorized,
+
+	// Request jwt is signed with an algorithm we don't accept from its issuer.
+	AuthErrCodeDisallowedAlgorithm: http.StatusUnauthorized,
+
+	// Request API key or basic credentials are unknown or wrong.
+	AuthErrCodeInvalidCredentials: http.StatusUnauthorized,
 }
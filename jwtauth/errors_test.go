@@ -27,6 +27,15 @@ func TestAuthErrorHTTPStatus(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, err.HTTPStatus())
 }
 
+func TestAuthErrorHTTPStatusValidationCodes(t *testing.T) {
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeExpired}).HTTPStatus())
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeNotYetValid}).HTTPStatus())
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeInvalidAudience}).HTTPStatus())
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeMissingClaim}).HTTPStatus())
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeDisallowedAlgorithm}).HTTPStatus())
+	assert.Equal(t, http.StatusUnauthorized, (&AuthError{Code: AuthErrCodeInvalidCredentials}).HTTPStatus())
+}
+
 func TestAuthErrorHTTPStatusUnknownCode(t *testing.T) {
 	err := &AuthError{
 		Code:  10000,
@@ -6,6 +6,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"net/http"
 
 	"github.com/anz-bank/sysl-go/jwtauth"
@@ -48,6 +49,43 @@ func NewIssuer(name string, keysize int) (Issuer, error) {
 	}, err
 }
 
+// NewHMACIssuer creates a new jwt token issuer that signs with the given shared secret using the
+// HS256 algorithm.
+func NewHMACIssuer(name string, secret []byte) (Issuer, error) {
+	kidUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Issuer{}, err
+	}
+	key := &jose.JSONWebKey{
+		KeyID:     kidUUID.String(),
+		Key:       secret,
+		Algorithm: string(jose.HS256),
+		Use:       "sig",
+	}
+	sig, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.HS256,
+		Key:       key,
+	}, &jose.SignerOptions{
+		ExtraHeaders: map[jose.HeaderKey]interface{}{
+			jose.HeaderType: "jwt",
+		},
+	})
+	return Issuer{
+		Signer: sig,
+		PubKey: key,
+		Name:   name,
+	}, err
+}
+
+// PublicKeyPEM returns the public key of the issuer as a PEM encoded PKIX public key.
+func (i Issuer) PublicKeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(i.PubKey.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
 // Issue issues a new jwt with the given claims.
 func (i Issuer) Issue(claims jwtauth.Claims) (string, error) {
 	return i.IssueFromMap(claims)
@@ -109,10 +147,15 @@ func (i Issuer) Authenticate(ctx context.Context, token string) (jwtauth.Claims,
 
 // Authenticator produces a standard authenticator with only this issuer as a trusted issuer.
 func (i Issuer) Authenticator() jwtauth.Authenticator {
+	validation := jwtauth.DefaultValidation
+	validation.Algorithms = []string{i.PubKey.Algorithm}
 	return &jwtauth.StdAuthenticator{
 		Verifiers: map[string]jwtauth.Verifier{
 			i.Name: i,
 		},
+		Validations: map[string]jwtauth.Validation{
+			i.Name: validation,
+		},
 	}
 }
 
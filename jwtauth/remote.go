@@ -7,7 +7,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -22,6 +24,32 @@ type RemoteJWKSIssuer struct {
 	url    string
 	client *http.Client
 	cache  *jwksCache
+
+	// oidcIssuerURL is set for issuers whose jwks url is discovered from their OpenID Connect
+	// metadata, in which case url is empty until discovery succeeds.
+	oidcIssuerURL string
+	urlMu         sync.Mutex
+}
+
+// CacheStats describes the use of the jwks cache of a RemoteJWKSIssuer.
+type CacheStats struct {
+	// Hits is the number of key lookups served from the cache.
+	Hits uint64
+	// Misses is the number of key lookups that found the cache expired.
+	Misses uint64
+	// Refreshes is the number of successful fetches of the jwks from the issuer.
+	Refreshes uint64
+	// RefreshErrors is the number of failed fetches of the jwks from the issuer.
+	RefreshErrors uint64
+	// Keys is the number of keys currently held in the cache.
+	Keys int
+	// LastRefresh is the time of the last successful fetch, or the zero time if there has been none.
+	LastRefresh time.Time
+}
+
+// CacheStatsProvider is implemented by verifiers that cache keys, such as RemoteJWKSIssuer.
+type CacheStatsProvider interface {
+	CacheStats() CacheStats
 }
 
 // NewRemoteJWKSIssuer creates a new RemoteJWKSIssuer.
@@ -37,15 +65,31 @@ func NewRemoteJWKSIssuer(ctx context.Context, issuer string, issuerURL string, c
 	if _, err := url.Parse(issuerURL); err != nil {
 		return nil, err
 	}
+	return startRemoteIssuer(ctx, issuer, &RemoteJWKSIssuer{url: issuerURL, client: client}, cacheTTL, cacheRefresh)
+}
+
+// NewOIDCIssuer creates a new RemoteJWKSIssuer for an OpenID Connect issuer.
+// UNSTABLE: This API should be avoided in favour of `VerifierFromIssuerConfig()`.
+//
+// The jwks url is discovered from the jwks_uri of the issuer metadata, served at
+// GET {issuerURL}/.well-known/openid-configuration. Discovery is retried on each cache refresh
+// until it succeeds. The cache parameters are the same as for NewRemoteJWKSIssuer.
+func NewOIDCIssuer(ctx context.Context, issuer string, issuerURL string, client *http.Client, cacheTTL time.Duration,
+	cacheRefresh time.Duration) (*RemoteJWKSIssuer, error) {
+	// Verify the issuer url is valid by parsing it
+	if _, err := url.Parse(issuerURL); err != nil {
+		return nil, err
+	}
+	return startRemoteIssuer(ctx, issuer, &RemoteJWKSIssuer{oidcIssuerURL: issuerURL, client: client}, cacheTTL, cacheRefresh)
+}
+
+func startRemoteIssuer(ctx context.Context, issuer string, r *RemoteJWKSIssuer, cacheTTL time.Duration,
+	cacheRefresh time.Duration) (*RemoteJWKSIssuer, error) {
 	if cacheTTL == 0 {
 		return nil, errors.New("Must have a non-zero cache ttl")
 	}
-	r := &RemoteJWKSIssuer{
-		url:    issuerURL,
-		client: client,
-		cache: &jwksCache{
-			ttl: cacheTTL,
-		},
+	r.cache = &jwksCache{
+		ttl: cacheTTL,
 	}
 	if _, err := r.refreshCache(); err != nil {
 		pkgLogger.Debug(ctx, "Error initializing jwks cache for remote issuer:", issuer, err)
@@ -103,8 +147,27 @@ func (r *RemoteJWKSIssuer) Verify(token *jwt.JSONWebToken, claims ...interface{}
 	return nil
 }
 
+// CacheStats returns the current stats of the jwks cache.
+func (r *RemoteJWKSIssuer) CacheStats() CacheStats {
+	return r.cache.stats()
+}
+
 func (r *RemoteJWKSIssuer) refreshCache() (*jose.JSONWebKeySet, error) {
-	resp, err := r.client.Get(r.url)
+	jwks, err := r.fetch()
+	if err != nil {
+		atomic.AddUint64(&r.cache.refreshErrors, 1)
+		return nil, err
+	}
+	r.cache.Put(jwks)
+	return jwks, nil
+}
+
+func (r *RemoteJWKSIssuer) fetch() (*jose.JSONWebKeySet, error) {
+	jwksURL, err := r.jwksURL()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Get(jwksURL)
 	if err != nil {
 		return nil, err
 	}
@@ -120,11 +183,59 @@ func (r *RemoteJWKSIssuer) refreshCache() (*jose.JSONWebKeySet, error) {
 	if err := json.Unmarshal(body, &newjwks); err != nil {
 		return nil, err
 	}
-	r.cache.Put(&newjwks)
 	return &newjwks, nil
 }
 
+// jwksURL returns the url of the jwks, discovering it first if need be.
+func (r *RemoteJWKSIssuer) jwksURL() (string, error) {
+	r.urlMu.Lock()
+	defer r.urlMu.Unlock()
+	if r.url == "" {
+		discovered, err := r.discoverJWKSURL()
+		if err != nil {
+			return "", errors.Wrap(err, "OpenID Connect discovery error")
+		}
+		r.url = discovered
+	}
+	return r.url, nil
+}
+
+// oidcMetadata holds the fields of the OpenID Connect issuer metadata used for discovery.
+type oidcMetadata struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+func (r *RemoteJWKSIssuer) discoverJWKSURL() (string, error) {
+	resp, err := r.client.Get(strings.TrimSuffix(r.oidcIssuerURL, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Received status %d from issuer", resp.StatusCode)
+	}
+	var metadata oidcMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return "", errors.Wrap(err, "Error reading issuer metadata")
+	}
+	// The issuer in the metadata must match the url it was retrieved from (OpenID Connect Discovery 1.0, section 4.3).
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(r.oidcIssuerURL, "/") {
+		return "", fmt.Errorf("Issuer metadata is for issuer %q", metadata.Issuer)
+	}
+	if metadata.JWKSURI == "" {
+		return "", errors.New("Issuer metadata has no jwks_uri")
+	}
+	return metadata.JWKSURI, nil
+}
+
 type jwksCache struct {
+	// Updated atomically, as lookups only hold the read lock. Kept first for 64-bit alignment.
+	hits          uint64
+	misses        uint64
+	refreshes     uint64
+	refreshErrors uint64
+
 	sync.RWMutex
 	cache   *jose.JSONWebKeySet
 	ttl     time.Duration
@@ -136,8 +247,10 @@ func (c *jwksCache) getKey(kid string) ([]jose.JSONWebKey, error) {
 	c.RLock()
 	defer c.RUnlock()
 	if c.ttl < time.Since(c.setTime) {
+		atomic.AddUint64(&c.misses, 1)
 		return nil, errors.New("Cache expired")
 	}
+	atomic.AddUint64(&c.hits, 1)
 	return c.cache.Key(kid), nil
 }
 
@@ -146,4 +259,21 @@ func (c *jwksCache) Put(jwks *jose.JSONWebKeySet) {
 	defer c.Unlock()
 	c.cache = jwks
 	c.setTime = time.Now()
+	atomic.AddUint64(&c.refreshes, 1)
+}
+
+func (c *jwksCache) stats() CacheStats {
+	c.RLock()
+	defer c.RUnlock()
+	s := CacheStats{
+		Hits:          atomic.LoadUint64(&c.hits),
+		Misses:        atomic.LoadUint64(&c.misses),
+		Refreshes:     atomic.LoadUint64(&c.refreshes),
+		RefreshErrors: atomic.LoadUint64(&c.refreshErrors),
+		LastRefresh:   c.setTime,
+	}
+	if c.cache != nil {
+		s.Keys = len(c.cache.Keys)
+	}
+	return s
 }
@@ -143,6 +143,30 @@ func TestRemoteJWKSVerifyFailsMaliciousJWT(t *testing.T) {
 
 // JWKS cache tests
 
+func TestRemoteJWKSCacheStats(t *testing.T) {
+	url, client := testClient()
+	ctx := testContext()
+	v, err := NewRemoteJWKSIssuer(ctx, "test-issuer", url, client, time.Minute, 0)
+	require.NoError(t, err)
+
+	jwtToken, err := jwt.ParseSigned(issueTestJWT())
+	require.NoError(t, err)
+	var claims Claims
+	require.NoError(t, v.Verify(jwtToken, &claims))
+	require.NoError(t, v.Verify(jwtToken, &claims))
+
+	stats := v.CacheStats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Zero(t, stats.Misses)
+	assert.Equal(t, uint64(1), stats.Refreshes)
+	assert.Zero(t, stats.RefreshErrors)
+	assert.NotZero(t, stats.Keys)
+	assert.False(t, stats.LastRefresh.IsZero())
+
+	auth := &StdAuthenticator{Verifiers: map[string]Verifier{"test-issuer": v, "other": testVerifier{}}}
+	assert.Equal(t, map[string]CacheStats{"test-issuer": stats}, auth.CacheStats())
+}
+
 func TestNewRemoteIssuerBadURL(t *testing.T) {
 	ctx := testContext()
 	_, client := testClient()
@@ -193,6 +217,7 @@ func TestRefreshCacheClientError(t *testing.T) {
 	_, err := v.refreshCache()
 	assert.Error(t, err)
 	assert.Zero(t, v.cache.setTime)
+	assert.Equal(t, uint64(1), v.CacheStats().RefreshErrors)
 }
 
 func TestRefreshCacheNotOK(t *testing.T) {
@@ -0,0 +1,67 @@
+package jwtauth
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// StaticKeyVerifier is a Verifier that verifies tokens against a single fixed key.
+//
+// The type of the key restricts the accepted signing algorithms: public keys only verify
+// asymmetric signatures and shared secrets only verify HMAC signatures.
+type StaticKeyVerifier struct {
+	key interface{}
+}
+
+// NewPublicKeyVerifier creates a StaticKeyVerifier from a PEM encoded public key.
+//
+// The PEM block may hold a PKIX public key ("PUBLIC KEY"), a PKCS #1 RSA public key
+// ("RSA PUBLIC KEY") or a certificate ("CERTIFICATE"), whose public key is used.
+func NewPublicKeyVerifier(pemBytes []byte) (*StaticKeyVerifier, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("No PEM data found in public key")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, errors.Errorf("Unsupported PEM block type for public key: %s", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing public key")
+	}
+	return &StaticKeyVerifier{key: key}, nil
+}
+
+// NewSharedSecretVerifier creates a StaticKeyVerifier from an HMAC shared secret.
+func NewSharedSecretVerifier(secret []byte) (*StaticKeyVerifier, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("Shared secret must not be empty")
+	}
+	return &StaticKeyVerifier{key: secret}, nil
+}
+
+// Verify implements the Verifier interface for StaticKeyVerifier.
+func (v *StaticKeyVerifier) Verify(token *jwt.JSONWebToken, claims ...interface{}) error {
+	if err := token.Claims(v.key, claims...); err != nil {
+		return &AuthError{
+			Code:  AuthErrCodeBadSignature,
+			Cause: errors.Wrap(err, "jwt verify error"),
+		}
+	}
+	return nil
+}
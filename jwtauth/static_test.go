@@ -0,0 +1,140 @@
+package jwtauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config/sensitive"
+	"github.com/anz-bank/sysl-go/jsontime"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/jwtauth/jwttest"
+	"github.com/stretchr/testify/require"
+)
+
+// authenticate authenticates a token issued by the given issuer with an authenticator built from
+// the given issuer config.
+func authenticate(t *testing.T, issuer jwttest.Issuer, ic jwtauth.IssuerConfig, client *http.Client) (jwtauth.Claims, error) {
+	ic.Name = issuer.Name
+	auth, err := jwtauth.AuthFromConfig(context.Background(), &jwtauth.Config{Issuers: []jwtauth.IssuerConfig{ic}},
+		func(string) *http.Client { return client })
+	require.NoError(t, err)
+	token, err := issuer.Issue(jwtauth.Claims{"sub": "me"})
+	require.NoError(t, err)
+	return auth.Authenticate(context.Background(), token)
+}
+
+func TestVerifierFromConfigPublicKey(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	key, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+
+	claims, err := authenticate(t, issuer, jwtauth.IssuerConfig{PublicKey: string(key)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "me", claims["sub"])
+
+	other, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	_, err = authenticate(t, other, jwtauth.IssuerConfig{PublicKey: string(key)}, nil)
+	require.Error(t, err)
+	require.Equal(t, jwtauth.AuthErrCodeBadSignature, err.(*jwtauth.AuthError).Code)
+}
+
+func TestVerifierFromConfigPublicKeyFile(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	key, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, ioutil.WriteFile(file, key, 0600))
+
+	claims, err := authenticate(t, issuer, jwtauth.IssuerConfig{PublicKeyFile: file}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "me", claims["sub"])
+
+	_, err = jwtauth.VerifierFromIssuerConfig(context.Background(), jwtauth.IssuerConfig{Name: "test", PublicKeyFile: file + ".missing"}, nil)
+	require.Error(t, err)
+}
+
+func TestVerifierFromConfigBadPublicKey(t *testing.T) {
+	_, err := jwtauth.VerifierFromIssuerConfig(context.Background(), jwtauth.IssuerConfig{Name: "test", PublicKey: "not a key"}, nil)
+	require.EqualError(t, err, "No PEM data found in public key")
+}
+
+func TestVerifierFromConfigSharedSecret(t *testing.T) {
+	issuer, err := jwttest.NewHMACIssuer("test", []byte("secret"))
+	require.NoError(t, err)
+	secret := sensitive.NewString("secret")
+
+	claims, err := authenticate(t, issuer, jwtauth.IssuerConfig{SharedSecret: &secret}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "me", claims["sub"])
+
+	wrong := sensitive.NewString("wrong")
+	_, err = authenticate(t, issuer, jwtauth.IssuerConfig{SharedSecret: &wrong}, nil)
+	require.Error(t, err)
+	require.Equal(t, jwtauth.AuthErrCodeBadSignature, err.(*jwtauth.AuthError).Code)
+}
+
+func TestSharedSecretRejectsPublicKeySignature(t *testing.T) {
+	// A verifier for a shared secret must not accept tokens signed with another algorithm.
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	secret := sensitive.NewString("secret")
+	_, err = authenticate(t, issuer, jwtauth.IssuerConfig{SharedSecret: &secret}, nil)
+	require.Error(t, err)
+}
+
+func TestVerifierFromConfigOIDCIssuer(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.Handle("/keys", issuer)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+
+	claims, err := authenticate(t, issuer, jwtauth.IssuerConfig{
+		OIDCIssuerURL: server.URL,
+		CacheTTL:      jsontime.Duration(time.Minute),
+	}, server.Client())
+	require.NoError(t, err)
+	require.Equal(t, "me", claims["sub"])
+}
+
+func TestVerifierFromConfigOIDCIssuerMismatch(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.Handle("/keys", issuer)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": "https://elsewhere", "jwks_uri": server.URL + "/keys"})
+	})
+
+	_, err = authenticate(t, issuer, jwtauth.IssuerConfig{
+		OIDCIssuerURL: server.URL,
+		CacheTTL:      jsontime.Duration(time.Minute),
+	}, server.Client())
+	require.Error(t, err)
+	require.Equal(t, jwtauth.AuthErrCodeUnknown, err.(*jwtauth.AuthError).Code)
+}
+
+func TestVerifierFromConfigMultipleSources(t *testing.T) {
+	secret := sensitive.NewString("secret")
+	_, err := jwtauth.VerifierFromIssuerConfig(context.Background(), jwtauth.IssuerConfig{
+		Name:         "test",
+		JWKSURL:      "http://localhost:8080",
+		SharedSecret: &secret,
+	}, nil)
+	require.EqualError(t, err, "jwtauth.Config: Must have exactly one of jwksUrl, oidcIssuerUrl, publicKey, publicKeyFile or sharedSecret set")
+}
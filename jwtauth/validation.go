@@ -0,0 +1,144 @@
+package jwtauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// DefaultLeeway is the clock skew allowed when validating the time claims of a token, if not configured.
+const DefaultLeeway = time.Second
+
+// HMACAlgorithms are the signing algorithms that use a shared secret.
+var HMACAlgorithms = []string{string(jose.HS256), string(jose.HS384), string(jose.HS512)}
+
+// AsymmetricAlgorithms are the signing algorithms that use a public key.
+var AsymmetricAlgorithms = []string{
+	string(jose.RS256), string(jose.RS384), string(jose.RS512),
+	string(jose.PS256), string(jose.PS384), string(jose.PS512),
+	string(jose.ES256), string(jose.ES384), string(jose.ES512),
+	string(jose.EdDSA),
+}
+
+// Validation defines how a token from an issuer is validated, in addition to its signature.
+type Validation struct {
+	// Audiences holds the expected audiences. If set, the aud claim must contain at least one of them.
+	Audiences []string
+	// RequiredClaims holds the names of the claims that must be present.
+	RequiredClaims []string
+	// Leeway is the clock skew allowed when validating the exp, nbf and iat claims.
+	Leeway time.Duration
+	// Algorithms holds the accepted signing algorithms. The "none" algorithm is never accepted.
+	Algorithms []string
+}
+
+// DefaultValidation is the validation of issuers without one: it accepts any audience, requires
+// no claims, allows a DefaultLeeway and accepts the AsymmetricAlgorithms.
+var DefaultValidation = Validation{
+	Leeway:     DefaultLeeway,
+	Algorithms: AsymmetricAlgorithms,
+}
+
+// checkAlgorithm verifies the token is signed with one of the accepted algorithms.
+func (v Validation) checkAlgorithm(token *jwt.JSONWebToken) error {
+	for _, h := range token.Headers {
+		if h.Algorithm == "" || h.Algorithm == "none" || !contains(v.Algorithms, h.Algorithm) {
+			return &AuthError{
+				Code:  AuthErrCodeDisallowedAlgorithm,
+				Cause: fmt.Errorf("signing algorithm not allowed: %q", h.Algorithm),
+			}
+		}
+	}
+	return nil
+}
+
+// checkTime verifies the exp, nbf and iat claims of the token.
+func (v Validation) checkTime(claims jwt.Claims, now time.Time) error {
+	err := claims.ValidateWithLeeway(jwt.Expected{Time: now}, v.Leeway)
+	switch err {
+	case nil:
+		return nil
+	case jwt.ErrExpired:
+		return &AuthError{Code: AuthErrCodeExpired, Cause: err}
+	case jwt.ErrNotValidYet, jwt.ErrIssuedInTheFuture:
+		return &AuthError{Code: AuthErrCodeNotYetValid, Cause: err}
+	default:
+		return &AuthError{Code: AuthErrCodeInvalidJWT, Cause: err}
+	}
+}
+
+// checkClaims verifies the audience and required claims of the verified claims of the token.
+func (v Validation) checkClaims(claims Claims) error {
+	if len(v.Audiences) > 0 {
+		audience, err := audienceOf(claims)
+		if err != nil {
+			return &AuthError{Code: AuthErrCodeInvalidJWT, Cause: errors.Wrap(err, "invalid aud claim")}
+		}
+		found := false
+		for _, aud := range v.Audiences {
+			if audience.Contains(aud) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &AuthError{
+				Code:  AuthErrCodeInvalidAudience,
+				Cause: fmt.Errorf("audience %q does not contain any of %q", []string(audience), v.Audiences),
+			}
+		}
+	}
+	for _, name := range v.RequiredClaims {
+		if _, ok := claims[name]; !ok {
+			return &AuthError{
+				Code:  AuthErrCodeMissingClaim,
+				Cause: fmt.Errorf("missing required claim: %s", name),
+			}
+		}
+	}
+	return nil
+}
+
+// audienceOf returns the aud claim, which may be a single string or an array of strings.
+func audienceOf(claims Claims) (jwt.Audience, error) {
+	raw, ok := claims["aud"]
+	if !ok {
+		return nil, nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var audience jwt.Audience
+	if err := json.Unmarshal(b, &audience); err != nil {
+		return nil, err
+	}
+	return audience, nil
+}
+
+// validateAlgorithms verifies the given algorithms are known and can be used with the given keys.
+func validateAlgorithms(algorithms []string, hmac bool) error {
+	allowed := AsymmetricAlgorithms
+	if hmac {
+		allowed = HMACAlgorithms
+	}
+	for _, alg := range algorithms {
+		if !contains(allowed, alg) {
+			return fmt.Errorf("algorithm %q cannot be used with the keys of the issuer (expected one of %q)", alg, allowed)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
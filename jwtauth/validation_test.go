@@ -0,0 +1,164 @@
+package jwtauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/sysl-go/config/sensitive"
+	"github.com/anz-bank/sysl-go/jsontime"
+	"github.com/anz-bank/sysl-go/jwtauth"
+	"github.com/anz-bank/sysl-go/jwtauth/jwttest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// requireAuthErrCode requires the error to be an AuthError with the given code.
+func requireAuthErrCode(t *testing.T, code int, err error) {
+	require.Error(t, err)
+	require.IsType(t, &jwtauth.AuthError{}, err)
+	require.Equal(t, code, err.(*jwtauth.AuthError).Code, err.Error())
+}
+
+// publicKeyAuthenticator returns an authenticator for the given issuer from the given config,
+// with the public key of the issuer.
+func publicKeyAuthenticator(t *testing.T, issuer jwttest.Issuer, ic jwtauth.IssuerConfig) jwtauth.Authenticator {
+	key, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+	ic.Name = issuer.Name
+	ic.PublicKey = string(key)
+	auth, err := jwtauth.AuthFromConfig(context.Background(), &jwtauth.Config{Issuers: []jwtauth.IssuerConfig{ic}},
+		func(string) *http.Client { return nil })
+	require.NoError(t, err)
+	return auth
+}
+
+func TestValidationAudience(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	auth := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{Audiences: []string{"me", "also-me"}})
+
+	for _, aud := range []interface{}{"also-me", []string{"other", "me"}} {
+		token, err := issuer.Issue(jwtauth.Claims{"aud": aud})
+		require.NoError(t, err)
+		_, err = auth.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+	}
+
+	token, err := issuer.Issue(jwtauth.Claims{"aud": []string{"other"}})
+	require.NoError(t, err)
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeInvalidAudience, err)
+
+	token, err = issuer.Issue(jwtauth.Claims{})
+	require.NoError(t, err)
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeInvalidAudience, err)
+}
+
+func TestValidationRequiredClaims(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	auth := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{RequiredClaims: []string{"sub", "scope"}})
+
+	token, err := issuer.Issue(jwtauth.Claims{"sub": "me", "scope": "READ"})
+	require.NoError(t, err)
+	_, err = auth.Authenticate(context.Background(), token)
+	require.NoError(t, err)
+
+	token, err = issuer.Issue(jwtauth.Claims{"sub": "me"})
+	require.NoError(t, err)
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeMissingClaim, err)
+	require.Contains(t, err.Error(), "missing required claim: scope")
+}
+
+func TestValidationTimeAndLeeway(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	strict := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{})
+	lenient := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{Leeway: jsontime.Duration(time.Minute)})
+
+	tests := []struct {
+		name   string
+		claims jwtauth.Claims
+		code   int
+	}{
+		{"expired", jwtauth.Claims{"exp": jwt.NewNumericDate(time.Now().Add(-30 * time.Second))}, jwtauth.AuthErrCodeExpired},
+		{"not before", jwtauth.Claims{"nbf": jwt.NewNumericDate(time.Now().Add(30 * time.Second))}, jwtauth.AuthErrCodeNotYetValid},
+		{"issued in the future", jwtauth.Claims{"iat": jwt.NewNumericDate(time.Now().Add(30 * time.Second))}, jwtauth.AuthErrCodeNotYetValid},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := issuer.Issue(tt.claims)
+			require.NoError(t, err)
+			_, err = strict.Authenticate(context.Background(), token)
+			requireAuthErrCode(t, tt.code, err)
+			_, err = lenient.Authenticate(context.Background(), token)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidationAlgorithms(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	token, err := issuer.Issue(jwtauth.Claims{})
+	require.NoError(t, err)
+
+	auth := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{Algorithms: []string{"RS256"}})
+	_, err = auth.Authenticate(context.Background(), token)
+	require.NoError(t, err)
+
+	auth = publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{Algorithms: []string{"ES256"}})
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeDisallowedAlgorithm, err)
+}
+
+func TestValidationRejectsHMACWithPublicKey(t *testing.T) {
+	// An attacker signs a token with HS256, using the (public) public key of the issuer as the secret.
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	key, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+	forger, err := jwttest.NewHMACIssuer("test", key)
+	require.NoError(t, err)
+	token, err := forger.Issue(jwtauth.Claims{})
+	require.NoError(t, err)
+
+	auth := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{})
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeDisallowedAlgorithm, err)
+}
+
+func TestValidationRejectsNone(t *testing.T) {
+	issuer, err := jwttest.NewIssuer("test", 2048)
+	require.NoError(t, err)
+	encode := base64.RawURLEncoding.EncodeToString
+	token := encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(`{"iss":"test"}`)) + "."
+
+	auth := publicKeyAuthenticator(t, issuer, jwtauth.IssuerConfig{})
+	_, err = auth.Authenticate(context.Background(), token)
+	requireAuthErrCode(t, jwtauth.AuthErrCodeDisallowedAlgorithm, err)
+}
+
+func TestValidationFromIssuerConfig(t *testing.T) {
+	v, err := jwtauth.ValidationFromIssuerConfig(jwtauth.IssuerConfig{})
+	require.NoError(t, err)
+	require.Equal(t, jwtauth.DefaultValidation, v)
+
+	secret := sensitive.NewString("secret")
+	v, err = jwtauth.ValidationFromIssuerConfig(jwtauth.IssuerConfig{SharedSecret: &secret})
+	require.NoError(t, err)
+	require.Equal(t, jwtauth.HMACAlgorithms, v.Algorithms)
+
+	_, err = jwtauth.ValidationFromIssuerConfig(jwtauth.IssuerConfig{JWKSURL: "http://localhost", Algorithms: []string{"HS256"}})
+	require.Error(t, err)
+	_, err = jwtauth.ValidationFromIssuerConfig(jwtauth.IssuerConfig{SharedSecret: &secret, Algorithms: []string{"RS256"}})
+	require.Error(t, err)
+	_, err = jwtauth.ValidationFromIssuerConfig(jwtauth.IssuerConfig{JWKSURL: "http://localhost", Algorithms: []string{"none"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,231 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NameKey is the key of the field that names a logger. The levels of named loggers can be
+// overridden through a LevelSwitch.
+const NameKey = "logger"
+
+// ParseLevel returns the level with the given name: error, warn, info or debug.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "error":
+		return ErrorLevel, nil
+	case "warn":
+		return WarnLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, must be one of error, warn, info or debug", name)
+	}
+}
+
+// LevelSwitch holds the levels of the loggers returned by NewSwitchableLogger, allowing them to
+// be changed at runtime.
+type LevelSwitch struct {
+	mu       sync.RWMutex
+	levels   levels
+	revert   *levels
+	revertAt time.Time
+	timer    *time.Timer
+	// generation counts the changes, so that a revert timer that fired before the change that
+	// stopped it can tell that it is stale.
+	generation uint64
+	onRevert   func(level Level)
+}
+
+type levels struct {
+	level     Level
+	overrides map[string]Level
+}
+
+// NewLevelSwitch returns a LevelSwitch set to the given level.
+func NewLevelSwitch(level Level) *LevelSwitch {
+	return &LevelSwitch{levels: levels{level: level}}
+}
+
+// Level returns the level of loggers without an override.
+func (s *LevelSwitch) Level() Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.levels.level
+}
+
+// Overrides returns the levels of named loggers that differ from the level of other loggers.
+func (s *LevelSwitch) Overrides() map[string]Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	overrides := make(map[string]Level, len(s.levels.overrides))
+	for name, level := range s.levels.overrides {
+		overrides[name] = level
+	}
+	return overrides
+}
+
+// RevertAt returns the time at which the levels revert, or the zero time if they do not.
+func (s *LevelSwitch) RevertAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revertAt
+}
+
+// Set sets the level of loggers and the overridden levels of named loggers. If revertAfter is
+// positive, the levels revert to those before the change (or before the first of a series of
+// changes that revert) after the given duration, otherwise the change is permanent.
+func (s *LevelSwitch) Set(level Level, overrides map[string]Level, revertAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.generation++
+	if revertAfter > 0 {
+		if s.revert == nil {
+			previous := s.levels
+			s.revert = &previous
+		}
+		s.revertAt = time.Now().Add(revertAfter)
+		generation := s.generation
+		s.timer = time.AfterFunc(revertAfter, func() { s.doRevert(generation) })
+	} else {
+		s.revert = nil
+		s.revertAt = time.Time{}
+	}
+	copied := make(map[string]Level, len(overrides))
+	for name, l := range overrides {
+		copied[name] = l
+	}
+	s.levels = levels{level: level, overrides: copied}
+}
+
+// OnRevert registers a function called with the level reverted to when a change reverts.
+func (s *LevelSwitch) OnRevert(fn func(level Level)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRevert = fn
+}
+
+// doRevert reverts the change of the given generation, unless it has since been changed again.
+func (s *LevelSwitch) doRevert(generation uint64) {
+	s.mu.Lock()
+	if s.revert == nil || s.generation != generation {
+		s.mu.Unlock()
+		return
+	}
+	s.levels = *s.revert
+	s.revert = nil
+	s.revertAt = time.Time{}
+	s.timer = nil
+	level, onRevert := s.levels.level, s.onRevert
+	s.mu.Unlock()
+	if onRevert != nil {
+		onRevert(level)
+	}
+}
+
+// levelOf returns the level of the logger with the given name.
+func (s *LevelSwitch) levelOf(name string) Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if override, ok := s.levels.overrides[name]; ok && name != "" {
+		return override
+	}
+	return s.levels.level
+}
+
+// NewSwitchableLogger returns a Logger that logs through the given logger at the levels held by
+// the given LevelSwitch, applied with the WithLevel method of the given logger. The name of the
+// logger, used to look up overridden levels, is the value of the NameKey field. Loggers whose
+// level is shared by all their instances (such as the Logrus logger) cannot reliably log named
+// loggers at different levels.
+func NewSwitchableLogger(logger Logger, levels *LevelSwitch) Logger {
+	return &switchableLogger{logger: logger, levels: levels}
+}
+
+type switchableLogger struct {
+	logger Logger
+	levels *LevelSwitch
+	name   string
+
+	m            sync.Mutex
+	leveled      Logger
+	leveledLevel Level
+}
+
+// current returns the logger at the current level of the logger, reusing the logger returned
+// by WithLevel until the level changes.
+func (l *switchableLogger) current() Logger {
+	level := l.levels.levelOf(l.name)
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.leveled == nil || l.leveledLevel != level {
+		l.leveled, l.leveledLevel = l.logger.WithLevel(level), level
+	}
+	return l.leveled
+}
+
+func (l *switchableLogger) Error(err error, message string) { l.current().Error(err, message) }
+func (l *switchableLogger) Warn(message string)             { l.current().Warn(message) }
+func (l *switchableLogger) Info(message string)             { l.current().Info(message) }
+func (l *switchableLogger) Debug(message string)            { l.current().Debug(message) }
+
+func (l *switchableLogger) WithStr(key string, value string) Logger {
+	name := l.name
+	if key == NameKey {
+		name = value
+	}
+	return &switchableLogger{logger: l.logger.WithStr(key, value), levels: l.levels, name: name}
+}
+
+func (l *switchableLogger) WithInt(key string, value int) Logger {
+	return l.with(l.logger.WithInt(key, value))
+}
+
+func (l *switchableLogger) WithDuration(key string, value time.Duration) Logger {
+	return l.with(l.logger.WithDuration(key, value))
+}
+
+func (l *switchableLogger) WithBool(key string, value bool) Logger {
+	return l.with(l.logger.WithBool(key, value))
+}
+
+func (l *switchableLogger) WithFloat(key string, value float64) Logger {
+	return l.with(l.logger.WithFloat(key, value))
+}
+
+func (l *switchableLogger) WithTime(key string, value time.Time) Logger {
+	return l.with(l.logger.WithTime(key, value))
+}
+
+func (l *switchableLogger) WithError(err error) Logger {
+	return l.with(l.logger.WithError(err))
+}
+
+func (l *switchableLogger) WithAny(key string, value interface{}) Logger {
+	return l.with(l.logger.WithAny(key, value))
+}
+
+func (l *switchableLogger) with(logger Logger) Logger {
+	return &switchableLogger{logger: logger, levels: l.levels, name: l.name}
+}
+
+// WithLevel returns a logger fixed at the given level, no longer switched by the LevelSwitch.
+func (l *switchableLogger) WithLevel(level Level) Logger {
+	return l.logger.WithLevel(level)
+}
+
+func (l *switchableLogger) Inject(ctx context.Context) (context.Context, func(ctx context.Context) Logger) {
+	ctx, restore := l.logger.Inject(ctx)
+	return ctx, func(c context.Context) Logger {
+		return &switchableLogger{logger: restore(c), levels: l.levels, name: l.name}
+	}
+}
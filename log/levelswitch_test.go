@@ -0,0 +1,95 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	pkg "github.com/anz-bank/pkg/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []Level{ErrorLevel, WarnLevel, InfoLevel, DebugLevel} {
+		parsed, err := ParseLevel(level.String())
+		require.NoError(t, err)
+		require.Equal(t, level, parsed)
+	}
+	_, err := ParseLevel("trace")
+	require.EqualError(t, err, `unknown log level "trace", must be one of error, warn, info or debug`)
+}
+
+func TestSwitchableLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	levels := NewLevelSwitch(InfoLevel)
+	ctx := PutLogger(context.Background(), NewSwitchableLogger(NewPkgLogger(pkg.Fields{}.WithConfigs(pkg.SetOutput(&buf))), levels))
+	named := WithStr(ctx, NameKey, "payload")
+
+	// Verify that debug level logs are ignored at the info level
+	Debug(ctx, "ignore-debug")
+	Debug(named, "ignore-named-debug")
+	require.NotContains(t, buf.String(), "ignore-debug")
+	require.NotContains(t, buf.String(), "ignore-named-debug")
+
+	// Verify that a changed level applies to existing loggers
+	levels.Set(DebugLevel, nil, 0)
+	Debug(ctx, "debug")
+	require.Contains(t, buf.String(), "debug")
+
+	// Verify that the level of a named logger can be overridden
+	buf.Reset()
+	levels.Set(InfoLevel, map[string]Level{"payload": DebugLevel}, 0)
+	Debug(ctx, "ignore-debug")
+	Debug(named, "named-debug")
+	require.NotContains(t, buf.String(), "ignore-debug")
+	require.Contains(t, buf.String(), "named-debug")
+	require.Equal(t, map[string]Level{"payload": DebugLevel}, levels.Overrides())
+
+	// Verify that a logger with a fixed level is no longer switched
+	fixed := PutLogger(ctx, GetLogger(ctx).WithLevel(DebugLevel))
+	Debug(fixed, "fixed-debug")
+	require.Contains(t, buf.String(), "fixed-debug")
+}
+
+func TestLevelSwitchRevert(t *testing.T) {
+	levels := NewLevelSwitch(InfoLevel)
+	reverted := make(chan Level, 1)
+	levels.OnRevert(func(level Level) { reverted <- level })
+
+	// Verify that a series of reverting changes reverts to the level before the first change
+	levels.Set(DebugLevel, map[string]Level{"payload": DebugLevel}, time.Hour)
+	levels.Set(ErrorLevel, nil, 10*time.Millisecond)
+	require.Equal(t, ErrorLevel, levels.Level())
+	require.False(t, levels.RevertAt().IsZero())
+	select {
+	case level := <-reverted:
+		require.Equal(t, InfoLevel, level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("levels did not revert")
+	}
+	require.Equal(t, InfoLevel, levels.Level())
+	require.Empty(t, levels.Overrides())
+	require.True(t, levels.RevertAt().IsZero())
+
+	// Verify that a permanent change cancels a pending revert
+	levels.Set(DebugLevel, nil, 10*time.Millisecond)
+	levels.Set(WarnLevel, nil, 0)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, WarnLevel, levels.Level())
+	require.True(t, levels.RevertAt().IsZero())
+}
+
+func TestLevelSwitchStaleRevert(t *testing.T) {
+	levels := NewLevelSwitch(InfoLevel)
+	levels.Set(DebugLevel, nil, time.Hour)
+	levels.mu.RLock()
+	stale := levels.generation
+	levels.mu.RUnlock()
+	levels.Set(ErrorLevel, nil, time.Hour)
+
+	// A timer that fired before the second change stopped it must not revert the second change.
+	levels.doRevert(stale)
+	require.Equal(t, ErrorLevel, levels.Level())
+	require.False(t, levels.RevertAt().IsZero())
+}
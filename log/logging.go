@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	zero "github.com/anz-bank/pkg/logging"
@@ -13,9 +14,13 @@ import (
 
 type loggerKey struct{}
 
+// ErrorKey is the key under which WithError persists an error.
+const ErrorKey = "error"
+
 // Logger is a component used to perform logging.
 type Logger interface {
 	Error(err error, message string)
+	Warn(message string)
 	Info(message string)
 	Debug(message string)
 
@@ -28,6 +33,22 @@ type Logger interface {
 	// WithDuration returns a new logger that persists the given key/value.
 	WithDuration(key string, value time.Duration) Logger
 
+	// WithBool returns a new logger that persists the given key/value.
+	WithBool(key string, value bool) Logger
+
+	// WithFloat returns a new logger that persists the given key/value.
+	WithFloat(key string, value float64) Logger
+
+	// WithTime returns a new logger that persists the given key/value.
+	WithTime(key string, value time.Time) Logger
+
+	// WithError returns a new logger that persists the given error under the ErrorKey key.
+	WithError(err error) Logger
+
+	// WithAny returns a new logger that persists the given key/value.
+	// Loggers that cannot persist arbitrary values persist their string representation.
+	WithAny(key string, value interface{}) Logger
+
 	// WithLevel returns a new logger that logs the given level or below.
 	WithLevel(level Level) Logger
 
@@ -39,12 +60,14 @@ type Logger interface {
 // Level represents the level at which a logger will log.
 // The currently supported values are:
 // 2 - Error
+// 3 - Warn
 // 4 - Info
 // 5 - Debug.
 type Level int
 
 const (
 	ErrorLevel = Level(logrus.ErrorLevel) // 2
+	WarnLevel  = Level(logrus.WarnLevel)  // 3
 	InfoLevel  = Level(logrus.InfoLevel)  // 4
 	DebugLevel = Level(logrus.DebugLevel) // 5
 )
@@ -53,6 +76,8 @@ func (l Level) String() string {
 	switch l {
 	case ErrorLevel:
 		return "error"
+	case WarnLevel:
+		return "warn"
 	case InfoLevel:
 		return "info"
 	default:
@@ -70,6 +95,16 @@ func Errorf(ctx context.Context, err error, format string, args ...interface{})
 	GetLogger(ctx).Error(err, fmt.Sprintf(format, args...))
 }
 
+// Warn logs the given message against the context found in the logger.
+func Warn(ctx context.Context, message string) {
+	GetLogger(ctx).Warn(message)
+}
+
+// Warnf logs the given message against the context found in the logger.
+func Warnf(ctx context.Context, format string, args ...interface{}) {
+	GetLogger(ctx).Warn(fmt.Sprintf(format, args...))
+}
+
 // Info logs the given message against the context found in the logger.
 func Info(ctx context.Context, message string) {
 	GetLogger(ctx).Info(message)
@@ -105,6 +140,31 @@ func WithDuration(ctx context.Context, key string, value time.Duration) context.
 	return PutLogger(ctx, GetLogger(ctx).WithDuration(key, value))
 }
 
+// WithBool returns the given context with a logger that persists the given key/value.
+func WithBool(ctx context.Context, key string, value bool) context.Context {
+	return PutLogger(ctx, GetLogger(ctx).WithBool(key, value))
+}
+
+// WithFloat returns the given context with a logger that persists the given key/value.
+func WithFloat(ctx context.Context, key string, value float64) context.Context {
+	return PutLogger(ctx, GetLogger(ctx).WithFloat(key, value))
+}
+
+// WithTime returns the given context with a logger that persists the given key/value.
+func WithTime(ctx context.Context, key string, value time.Time) context.Context {
+	return PutLogger(ctx, GetLogger(ctx).WithTime(key, value))
+}
+
+// WithError returns the given context with a logger that persists the given error.
+func WithError(ctx context.Context, err error) context.Context {
+	return PutLogger(ctx, GetLogger(ctx).WithError(err))
+}
+
+// WithAny returns the given context with a logger that persists the given key/value.
+func WithAny(ctx context.Context, key string, value interface{}) context.Context {
+	return PutLogger(ctx, GetLogger(ctx).WithAny(key, value))
+}
+
 // WithLevel returns the given context with a logger that logs at the given level.
 func WithLevel(ctx context.Context, level Level) context.Context {
 	return PutLogger(ctx, GetLogger(ctx).WithLevel(level))
@@ -125,6 +185,14 @@ func PutLogger(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, loggerKey{}, fn)
 }
 
+// errorString returns the message of the given error, or an empty string for a nil error.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 // NewDefaultLogger returns a logger that is regarded as the default logger to use within an
 // application when no logger configuration is provided.
 func NewDefaultLogger() Logger {
@@ -145,6 +213,9 @@ func (l *pkgLogger) Error(err error, message string) { l.logger().Error(err, mes
 func (l *pkgLogger) Info(message string)             { l.logger().Info(message) }
 func (l *pkgLogger) Debug(message string)            { l.logger().Debug(message) }
 
+// Warn logs at the info level, as the pkg/log logger has no warn level.
+func (l *pkgLogger) Warn(message string) { l.logger().Info(message) }
+
 func (l *pkgLogger) WithStr(key string, value string) Logger {
 	return &pkgLogger{l.fields.With(key, value)}
 }
@@ -157,6 +228,26 @@ func (l *pkgLogger) WithDuration(key string, value time.Duration) Logger {
 	return &pkgLogger{l.fields.With(key, value)}
 }
 
+func (l *pkgLogger) WithBool(key string, value bool) Logger {
+	return &pkgLogger{l.fields.With(key, value)}
+}
+
+func (l *pkgLogger) WithFloat(key string, value float64) Logger {
+	return &pkgLogger{l.fields.With(key, value)}
+}
+
+func (l *pkgLogger) WithTime(key string, value time.Time) Logger {
+	return &pkgLogger{l.fields.With(key, value)}
+}
+
+func (l *pkgLogger) WithError(err error) Logger {
+	return &pkgLogger{l.fields.With(ErrorKey, errorString(err))}
+}
+
+func (l *pkgLogger) WithAny(key string, value interface{}) Logger {
+	return &pkgLogger{l.fields.With(key, value)}
+}
+
 func (l *pkgLogger) WithLevel(level Level) Logger {
 	return &pkgLogger{l.fields.WithConfigs(pkg.SetVerboseMode(level == DebugLevel))}
 }
@@ -181,6 +272,9 @@ func (l *zeroPkgLogger) Error(err error, message string) { l.logger.Error(err).M
 func (l *zeroPkgLogger) Info(message string)             { l.logger.Info().Msg(message) }
 func (l *zeroPkgLogger) Debug(message string)            { l.logger.Debug().Msg(message) }
 
+// Warn logs at the info level, as the pkg/logging logger has no warn level.
+func (l *zeroPkgLogger) Warn(message string) { l.logger.Info().Msg(message) }
+
 func (l *zeroPkgLogger) WithStr(key string, value string) Logger {
 	return &zeroPkgLogger{l.logger.WithStr(key, value)}
 }
@@ -193,12 +287,34 @@ func (l *zeroPkgLogger) WithDuration(key string, value time.Duration) Logger {
 	return &zeroPkgLogger{l.logger.WithDur(key, value)}
 }
 
+func (l *zeroPkgLogger) WithBool(key string, value bool) Logger {
+	return &zeroPkgLogger{l.logger.WithBool(key, value)}
+}
+
+// WithFloat persists the value as a string, as the pkg/logging logger has no float fields.
+func (l *zeroPkgLogger) WithFloat(key string, value float64) Logger {
+	return &zeroPkgLogger{l.logger.WithStr(key, strconv.FormatFloat(value, 'g', -1, 64))}
+}
+
+func (l *zeroPkgLogger) WithTime(key string, value time.Time) Logger {
+	return &zeroPkgLogger{l.logger.WithTime(key, value)}
+}
+
+func (l *zeroPkgLogger) WithError(err error) Logger {
+	return &zeroPkgLogger{l.logger.WithStr(ErrorKey, errorString(err))}
+}
+
+// WithAny persists the value as a string, as the pkg/logging logger has no interface fields.
+func (l *zeroPkgLogger) WithAny(key string, value interface{}) Logger {
+	return &zeroPkgLogger{l.logger.WithStr(key, fmt.Sprintf("%v", value))}
+}
+
 func (l *zeroPkgLogger) WithLevel(level Level) Logger {
 	var lvl zero.Level
 	switch level {
 	case ErrorLevel:
 		lvl = zero.ErrorLevel
-	case InfoLevel:
+	case WarnLevel, InfoLevel:
 		lvl = zero.InfoLevel
 	case DebugLevel:
 		lvl = zero.DebugLevel
@@ -226,6 +342,7 @@ type logrusLogger struct {
 func (l *logrusLogger) entry() *logrus.Entry { return l.logger.WithFields(l.fields) }
 
 func (l *logrusLogger) Error(err error, message string) { l.entry().WithError(err).Error(message) }
+func (l *logrusLogger) Warn(message string)             { l.entry().Warn(message) }
 func (l *logrusLogger) Info(message string)             { l.entry().Info(message) }
 func (l *logrusLogger) Debug(message string)            { l.entry().Debug(message) }
 
@@ -234,6 +351,11 @@ func (l *logrusLogger) WithInt(key string, value int) Logger    { return l.withF
 func (l *logrusLogger) WithDuration(key string, value time.Duration) Logger {
 	return l.withField(key, value)
 }
+func (l *logrusLogger) WithBool(key string, value bool) Logger       { return l.withField(key, value) }
+func (l *logrusLogger) WithFloat(key string, value float64) Logger   { return l.withField(key, value) }
+func (l *logrusLogger) WithTime(key string, value time.Time) Logger  { return l.withField(key, value) }
+func (l *logrusLogger) WithError(err error) Logger                   { return l.withField(ErrorKey, err) }
+func (l *logrusLogger) WithAny(key string, value interface{}) Logger { return l.withField(key, value) }
 
 func (l *logrusLogger) withField(key string, value interface{}) Logger {
 	fields := make(map[string]interface{})
@@ -251,6 +373,8 @@ func (l *logrusLogger) WithLevel(level Level) Logger {
 	switch level {
 	case ErrorLevel:
 		lvl = logrus.ErrorLevel
+	case WarnLevel:
+		lvl = logrus.WarnLevel
 	case InfoLevel:
 		lvl = logrus.InfoLevel
 	case DebugLevel:
@@ -59,6 +59,10 @@ func testLoggerEvents(t *testing.T, newLogger func(*bytes.Buffer) Logger) {
 	require.Contains(t, buf.String(), "error")
 	require.Contains(t, buf.String(), "format")
 
+	// Verify that a warn level log is logged
+	Warn(ctx, "warn")
+	require.Contains(t, buf.String(), "warn")
+
 	// Verify that an info level log is logged
 	Info(ctx, "info")
 	require.Contains(t, buf.String(), "info")
@@ -93,6 +97,41 @@ func testLoggerPersistence(t *testing.T, newLogger func(*bytes.Buffer) Logger) {
 	require.Contains(t, buf.String(), "duration_event")
 	require.Contains(t, buf.String(), "duration_key")
 	require.True(t, strings.Contains(buf.String(), "3600000") || strings.Contains(buf.String(), "1h0m0s"))
+
+	// Verify that a bool is persisted within the context
+	ctx = WithBool(ctx, "bool_key", true)
+	Info(ctx, "bool_event")
+	require.Contains(t, buf.String(), "bool_event")
+	require.Contains(t, buf.String(), "bool_key")
+	require.Contains(t, buf.String(), "true")
+
+	// Verify that a float is persisted within the context
+	ctx = WithFloat(ctx, "float_key", 1.25)
+	Info(ctx, "float_event")
+	require.Contains(t, buf.String(), "float_event")
+	require.Contains(t, buf.String(), "float_key")
+	require.Contains(t, buf.String(), "1.25")
+
+	// Verify that a time is persisted within the context
+	ctx = WithTime(ctx, "time_key", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	Info(ctx, "time_event")
+	require.Contains(t, buf.String(), "time_event")
+	require.Contains(t, buf.String(), "time_key")
+	require.Contains(t, buf.String(), "2021-03-04")
+
+	// Verify that an error is persisted within the context
+	ctx = WithError(ctx, errors.New("error_value"))
+	Info(ctx, "error_event")
+	require.Contains(t, buf.String(), "error_event")
+	require.Contains(t, buf.String(), ErrorKey)
+	require.Contains(t, buf.String(), "error_value")
+
+	// Verify that an arbitrary value is persisted within the context
+	ctx = WithAny(ctx, "any_key", struct{ Name string }{"any_value"})
+	Info(ctx, "any_event")
+	require.Contains(t, buf.String(), "any_event")
+	require.Contains(t, buf.String(), "any_key")
+	require.Contains(t, buf.String(), "any_value")
 }
 
 // Test that a logger logs, or ignores, log levels appropriately.
@@ -112,6 +151,27 @@ func testLoggerLevel(t *testing.T, newLogger func(*bytes.Buffer) Logger) {
 	require.NotContains(t, buf.String(), "ignore-info")
 }
 
+func TestLogrusLoggerWarnLevel(t *testing.T) {
+	buf := bytes.Buffer{}
+	lrs := logrus.New()
+	lrs.Out = &buf
+	ctx := PutLogger(context.Background(), NewLogrusLogger(lrs).WithLevel(WarnLevel))
+
+	// Verify that an info level log is ignored and a warn level log is logged
+	Info(ctx, "ignore-info")
+	Warnf(ctx, "warn-%d", 1)
+	require.NotContains(t, buf.String(), "ignore-info")
+	require.Contains(t, buf.String(), "level=warning")
+	require.Contains(t, buf.String(), "warn-1")
+}
+
+func TestLevelString(t *testing.T) {
+	require.Equal(t, "error", ErrorLevel.String())
+	require.Equal(t, "warn", WarnLevel.String())
+	require.Equal(t, "info", InfoLevel.String())
+	require.Equal(t, "debug", DebugLevel.String())
+}
+
 // Test the usage of the native logger can be interleaved with the wrapped logger.
 func testLoggerInterleave(t *testing.T,
 	newLogger func(*bytes.Buffer) Logger,
@@ -0,0 +1,218 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type slogKey struct{}
+
+// SlogLoggerToContext puts the given slog logger in the context. A server created with the
+// context logs through the slog logger unless a Logger is put in the context.
+func SlogLoggerToContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, slogKey{}, logger)
+}
+
+// SlogLoggerFromContext returns a Logger that uses the slog logger in the context, or nil if no
+// slog logger can be found.
+func SlogLoggerFromContext(ctx context.Context) Logger {
+	logger, _ := ctx.Value(slogKey{}).(*slog.Logger)
+	if logger == nil {
+		return nil
+	}
+	return NewSlogLogger(logger)
+}
+
+// NewSlogLogger returns an implementation of Logger that uses the log/slog logger. The level set
+// with WithLevel applies in addition to the level of the handler of the slog logger.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger, DebugLevel}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+	level  Level
+}
+
+func (l *slogLogger) Error(err error, message string) {
+	if l.level >= ErrorLevel {
+		l.logger.Error(message, slog.Any(ErrorKey, err))
+	}
+}
+
+func (l *slogLogger) Warn(message string) {
+	if l.level >= WarnLevel {
+		l.logger.Warn(message)
+	}
+}
+
+func (l *slogLogger) Info(message string) {
+	if l.level >= InfoLevel {
+		l.logger.Info(message)
+	}
+}
+
+func (l *slogLogger) Debug(message string) {
+	if l.level >= DebugLevel {
+		l.logger.Debug(message)
+	}
+}
+
+func (l *slogLogger) WithStr(key string, value string) Logger {
+	return l.with(slog.String(key, value))
+}
+
+func (l *slogLogger) WithInt(key string, value int) Logger {
+	return l.with(slog.Int(key, value))
+}
+
+func (l *slogLogger) WithDuration(key string, value time.Duration) Logger {
+	return l.with(slog.Duration(key, value))
+}
+
+func (l *slogLogger) WithBool(key string, value bool) Logger {
+	return l.with(slog.Bool(key, value))
+}
+
+func (l *slogLogger) WithFloat(key string, value float64) Logger {
+	return l.with(slog.Float64(key, value))
+}
+
+func (l *slogLogger) WithTime(key string, value time.Time) Logger {
+	return l.with(slog.Time(key, value))
+}
+
+func (l *slogLogger) WithError(err error) Logger {
+	return l.with(slog.Any(ErrorKey, err))
+}
+
+func (l *slogLogger) WithAny(key string, value interface{}) Logger {
+	return l.with(slog.Any(key, value))
+}
+
+func (l *slogLogger) with(attr slog.Attr) Logger {
+	return &slogLogger{l.logger.With(attr), l.level}
+}
+
+func (l *slogLogger) WithLevel(level Level) Logger {
+	return &slogLogger{l.logger, level}
+}
+
+func (l *slogLogger) Inject(ctx context.Context) (context.Context, func(ctx context.Context) Logger) {
+	// Put and restore the logger natively, as for the pkg loggers, so that attributes added to the
+	// slog logger in the context aren't lost if the application uses both a native and wrapped logger.
+	return SlogLoggerToContext(ctx, l.logger), func(c context.Context) Logger {
+		logger, _ := c.Value(slogKey{}).(*slog.Logger)
+		return &slogLogger{logger, l.level}
+	}
+}
+
+// NewSlogHandler returns a slog.Handler that writes records through the Logger in the context
+// given to the slog logger (see PutLogger), or through the given fallback Logger if the context
+// has none. Levels are filtered by the Logger rather than the handler.
+func NewSlogHandler(fallback Logger) slog.Handler {
+	return &loggerHandler{fallback: fallback}
+}
+
+type loggerHandler struct {
+	fallback Logger
+	attrs    []slog.Attr
+	group    string
+}
+
+func (h *loggerHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *loggerHandler) Handle(ctx context.Context, r slog.Record) error {
+	logger := GetLogger(ctx)
+	if logger == nil {
+		logger = h.fallback
+	}
+	if logger == nil {
+		return nil
+	}
+	var err error
+	for _, attr := range h.attrs {
+		logger = withAttr(logger, "", attr, &err)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		logger = withAttr(logger, h.group, attr, &err)
+		return true
+	})
+	// The error of an error record is logged by Error, so is only added as a field to others.
+	if err != nil && r.Level < slog.LevelError {
+		logger = logger.WithError(err)
+	}
+	switch {
+	case r.Level >= slog.LevelError:
+		if err == nil {
+			err = errors.New(r.Message)
+		}
+		logger.Error(err, r.Message)
+	case r.Level >= slog.LevelWarn:
+		logger.Warn(r.Message)
+	case r.Level >= slog.LevelInfo:
+		logger.Info(r.Message)
+	default:
+		logger.Debug(r.Message)
+	}
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	withAttrs := &loggerHandler{h.fallback, append([]slog.Attr{}, h.attrs...), h.group}
+	for _, attr := range attrs {
+		withAttrs.attrs = append(withAttrs.attrs, slog.Attr{Key: h.group + attr.Key, Value: attr.Value})
+	}
+	return withAttrs
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &loggerHandler{h.fallback, h.attrs, h.group + name + "."}
+}
+
+// withAttr returns the logger with the given attribute, prefixed by the given group, as a field.
+// Empty attributes are ignored. The value of an ungrouped attribute with the ErrorKey key that
+// holds an error is recorded in err instead.
+func withAttr(logger Logger, group string, attr slog.Attr, err *error) Logger {
+	if attr.Equal(slog.Attr{}) {
+		return logger
+	}
+	value := attr.Value.Resolve()
+	key := group + attr.Key
+	switch value.Kind() {
+	case slog.KindString:
+		return logger.WithStr(key, value.String())
+	case slog.KindInt64:
+		return logger.WithInt(key, int(value.Int64()))
+	case slog.KindFloat64:
+		return logger.WithFloat(key, value.Float64())
+	case slog.KindBool:
+		return logger.WithBool(key, value.Bool())
+	case slog.KindDuration:
+		return logger.WithDuration(key, value.Duration())
+	case slog.KindTime:
+		return logger.WithTime(key, value.Time())
+	case slog.KindGroup:
+		prefix := key + "."
+		if attr.Key == "" {
+			prefix = group
+		}
+		for _, a := range value.Group() {
+			logger = withAttr(logger, prefix, a, err)
+		}
+		return logger
+	}
+	if e, ok := value.Any().(error); ok && key == ErrorKey {
+		*err = e
+		return logger
+	}
+	return logger.WithAny(key, value.Any())
+}
@@ -0,0 +1,138 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger(t *testing.T) {
+	newLogger := func(buf *bytes.Buffer) Logger {
+		return NewSlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	}
+	nativePersist := func(ctx context.Context, k string, v string) context.Context {
+		return SlogLoggerToContext(ctx, ctx.Value(slogKey{}).(*slog.Logger).With(k, v))
+	}
+	nativeLog := func(ctx context.Context, buf *bytes.Buffer, str string) {
+		ctx.Value(slogKey{}).(*slog.Logger).Info(str)
+	}
+	testLoggerEvents(t, newLogger)
+	testLoggerLevel(t, newLogger)
+	testLoggerPersistence(t, newLogger)
+	testLoggerInterleave(t, newLogger, nativePersist, nativeLog)
+}
+
+func TestSlogLoggerFromContext(t *testing.T) {
+	require.Nil(t, SlogLoggerFromContext(context.Background()))
+
+	buf := bytes.Buffer{}
+	ctx := SlogLoggerToContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	logger := SlogLoggerFromContext(ctx)
+	require.NotNil(t, logger)
+	logger.Info("hello")
+	require.Contains(t, buf.String(), "hello")
+}
+
+func TestSlogHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	lrs := logrus.New()
+	lrs.Out = &buf
+	ctx := PutLogger(context.Background(), NewLogrusLogger(lrs).WithLevel(DebugLevel))
+	logger := slog.New(NewSlogHandler(nil)).With("a", 1).WithGroup("g")
+
+	// Verify that records are logged through the logger in the context, with their attributes
+	logger.InfoContext(ctx, "info", "b", true, slog.Group("h", "c", 1.5))
+	require.Contains(t, buf.String(), "level=info")
+	require.Contains(t, buf.String(), "msg=info")
+	require.Contains(t, buf.String(), "a=1")
+	require.Contains(t, buf.String(), "g.b=true")
+	require.Contains(t, buf.String(), "g.h.c=1.5")
+
+	// Verify that the levels of records are kept
+	buf.Reset()
+	logger.WarnContext(ctx, "warn")
+	require.Contains(t, buf.String(), "level=warning")
+	buf.Reset()
+	logger.DebugContext(ctx, "debug")
+	require.Contains(t, buf.String(), "level=debug")
+
+	// Verify that an error attribute is logged as the error of the log
+	buf.Reset()
+	slog.New(NewSlogHandler(nil)).ErrorContext(ctx, "failed", ErrorKey, errors.New("boom"))
+	require.Contains(t, buf.String(), "level=error")
+	require.Contains(t, buf.String(), "error=boom")
+	require.Contains(t, buf.String(), "msg=failed")
+
+	// Verify that the error is logged once, through a logger that doesn't merge duplicate fields
+	var slogBuf bytes.Buffer
+	slogCtx := PutLogger(context.Background(), NewSlogLogger(slog.New(slog.NewTextHandler(&slogBuf, nil))))
+	slog.New(NewSlogHandler(nil)).ErrorContext(slogCtx, "failed", ErrorKey, errors.New("boom"))
+	require.Equal(t, 1, strings.Count(slogBuf.String(), "error=boom"), slogBuf.String())
+	slogBuf.Reset()
+	slog.New(NewSlogHandler(nil)).WarnContext(slogCtx, "failing", ErrorKey, errors.New("boom"))
+	require.Equal(t, 1, strings.Count(slogBuf.String(), "error=boom"), slogBuf.String())
+
+	// Verify that records are logged through the fallback logger if the context has none
+	buf.Reset()
+	slog.New(NewSlogHandler(NewLogrusLogger(lrs))).Info("fallback")
+	require.Contains(t, buf.String(), "fallback")
+
+	// Verify that records are dropped without a logger
+	require.NotPanics(t, func() { slog.New(NewSlogHandler(nil)).Info("dropped") })
+}
+
+func TestSlogHandlerConformance(t *testing.T) {
+	buf := bytes.Buffer{}
+	lrs := logrus.New()
+	lrs.Out = &buf
+	lrs.Formatter = &logrus.JSONFormatter{}
+	handler := NewSlogHandler(NewLogrusLogger(lrs).WithLevel(DebugLevel))
+
+	err := slogtest.TestHandler(handler, func() []map[string]interface{} {
+		var results []map[string]interface{}
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal(line, &fields))
+			results = append(results, nestGroups(fields))
+		}
+		return results
+	})
+	if err == nil {
+		return
+	}
+	// The time of a record is added by the Logger, so a zero time can't be dropped.
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if !strings.Contains(e.Error(), "zero Record.Time") {
+			t.Error(e)
+		}
+	}
+}
+
+// nestGroups returns the fields with the grouped fields, whose keys are prefixed by their groups,
+// nested in a map for each group.
+func nestGroups(fields map[string]interface{}) map[string]interface{} {
+	nested := map[string]interface{}{}
+	for key, value := range fields {
+		m := nested
+		path := strings.Split(key, ".")
+		for _, group := range path[:len(path)-1] {
+			if _, ok := m[group].(map[string]interface{}); !ok {
+				m[group] = map[string]interface{}{}
+			}
+			m = m[group].(map[string]interface{})
+		}
+		m[path[len(path)-1]] = value
+	}
+	return nested
+}
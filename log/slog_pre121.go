@@ -0,0 +1,12 @@
+//go:build !go1.21
+// +build !go1.21
+
+package log
+
+import "context"
+
+// SlogLoggerFromContext returns a Logger that uses the slog logger in the context, or nil if no
+// slog logger can be found. The log/slog package requires Go 1.21, so this always returns nil.
+func SlogLoggerFromContext(ctx context.Context) Logger {
+	return nil
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Types of gRPC method, as reported in the grpc_type label.
+const (
+	grpcTypeUnary        = "unary"
+	grpcTypeClientStream = "client_stream"
+	grpcTypeServerStream = "server_stream"
+	grpcTypeBidiStream   = "bidi_stream"
+)
+
+// GRPCServerMetrics records the total number and duration of the calls handled by a gRPC server,
+// partitioned by method type, method and status code.
+type GRPCServerMetrics struct {
+	handled *prometheus.CounterVec
+	latency *prometheus.SummaryVec
+}
+
+// NewGRPCServerMetrics returns a new GRPCServerMetrics registered against the given registry.
+func NewGRPCServerMetrics(registry *prometheus.Registry, serviceName string) *GRPCServerMetrics {
+	handledCounterVec := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "grpc_server_handled_total",
+			Help:        "gRPC calls handled, by method type, method and status code",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		},
+		[]string{"grpc_type", "grpc_method", "grpc_code"},
+	)
+	registry.MustRegister(handledCounterVec)
+
+	latencySummary := prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:        "grpc_server_handling_seconds",
+			Help:        "Duration of the handled call, by method type, method and status code",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+			Objectives:  map[float64]float64{0.50: 0.01, 0.90: 0.001, 0.95: 0.001, 0.99: 0.0001},
+		},
+		[]string{"grpc_type", "grpc_method", "grpc_code"},
+	)
+	registry.MustRegister(latencySummary)
+
+	return &GRPCServerMetrics{handled: handledCounterVec, latency: latencySummary}
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that records the metrics of each call.
+func (m *GRPCServerMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.updateMetrics(grpcTypeUnary, info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor that records the metrics of each call.
+func (m *GRPCServerMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.updateMetrics(streamType(info), info.FullMethod, err, start)
+		return err
+	}
+}
+
+func (m *GRPCServerMetrics) updateMetrics(grpcType, method string, err error, start time.Time) {
+	code := status.Code(err).String()
+	durationSecs := time.Since(start).Seconds()
+	m.handled.WithLabelValues(grpcType, method, code).Inc()
+	m.latency.WithLabelValues(grpcType, method, code).Observe(durationSecs)
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return grpcTypeBidiStream
+	case info.IsClientStream:
+		return grpcTypeClientStream
+	default:
+		return grpcTypeServerStream
+	}
+}
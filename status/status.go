@@ -17,6 +17,8 @@ type Service struct {
 	BuildMetadata *BuildMetadata
 	Config        *config.LibraryConfig
 	Services      []handlerinitialiser.HandlerInitialiser
+	// AuthorizationRules holds the effective authorization rule expression of each endpoint.
+	AuthorizationRules map[string]string
 }
 
 func WireRoutes(r chi.Router, s *Service) {
@@ -58,6 +60,8 @@ type Response struct {
 	BuildMetadata BuildMetadata  `json:"build_metadata"`
 	Config        ResponseConfig `json:"config"`
 	Status        string         `json:"status"`
+	// AuthorizationRules holds the effective authorization rule expression of each endpoint.
+	AuthorizationRules map[string]string `json:"authorization_rules,omitempty"`
 }
 
 func (s *Service) buildResponseConfig() ResponseConfig {
@@ -74,9 +78,10 @@ func (s *Service) buildResponseConfig() ResponseConfig {
 
 func (s *Service) HandleGetStatus(rw http.ResponseWriter, r *http.Request) {
 	response := Response{
-		BuildMetadata: *s.BuildMetadata,
-		Config:        s.buildResponseConfig(),
-		Status:        "online",
+		BuildMetadata:      *s.BuildMetadata,
+		Config:             s.buildResponseConfig(),
+		Status:             "online",
+		AuthorizationRules: s.AuthorizationRules,
 	}
 
 	buffer := bytes.Buffer{}
@@ -41,6 +41,7 @@ func (l *TestLogger) EntryCount() int {
 }
 
 func (l *TestLogger) Error(err error, message string) { l.log(log.ErrorLevel, err, message) }
+func (l *TestLogger) Warn(message string)             { l.log(log.WarnLevel, nil, message) }
 func (l *TestLogger) Info(message string)             { l.log(log.InfoLevel, nil, message) }
 func (l *TestLogger) Debug(message string)            { l.log(log.DebugLevel, nil, message) }
 
@@ -67,6 +68,26 @@ func (l *TestLogger) WithDuration(key string, value time.Duration) log.Logger {
 	return l.withField(key, value)
 }
 
+func (l *TestLogger) WithBool(key string, value bool) log.Logger {
+	return l.withField(key, value)
+}
+
+func (l *TestLogger) WithFloat(key string, value float64) log.Logger {
+	return l.withField(key, value)
+}
+
+func (l *TestLogger) WithTime(key string, value time.Time) log.Logger {
+	return l.withField(key, value)
+}
+
+func (l *TestLogger) WithError(err error) log.Logger {
+	return l.withField(log.ErrorKey, err)
+}
+
+func (l *TestLogger) WithAny(key string, value interface{}) log.Logger {
+	return l.withField(key, value)
+}
+
 func (l *TestLogger) withField(key string, value interface{}) log.Logger {
 	fields := l.copyFields()
 	fields[key] = value
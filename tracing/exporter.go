@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"sync"
+)
+
+// Exporter receives completed, sampled spans, e.g. to send them to a tracing backend.
+// ExportSpan is called synchronously when a span ends so must not block.
+type Exporter interface {
+	ExportSpan(span SpanData)
+}
+
+// ExporterFunc adapts a function to an Exporter.
+type ExporterFunc func(span SpanData)
+
+func (f ExporterFunc) ExportSpan(span SpanData) {
+	f(span)
+}
+
+// InMemoryExporter retains exported spans in memory, for tests.
+type InMemoryExporter struct {
+	m     sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter returns an empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span SpanData) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the spans exported so far, in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return append([]SpanData{}, e.spans...)
+}
+
+// Reset discards the spans exported so far.
+func (e *InMemoryExporter) Reset() {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.spans = nil
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a unary server interceptor that puts the given tracer in the context
+// and records a server span for each call, as the child of the span context propagated in the
+// incoming metadata if any.
+func UnaryServerInterceptor(tracer *Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = PutTracer(ctx, tracer)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = tracer.Extract(ctx, MetadataCarrier(md))
+		}
+		ctx, span := tracer.Start(ctx, info.FullMethod, SpanKindServer)
+		defer span.End()
+		span.SetAttribute(AttributeRPCMethod, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		span.SetAttribute(AttributeRPCStatusCode, int(status.Code(err)))
+		if err != nil {
+			span.RecordError(err)
+		}
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns a unary client interceptor that records a client span for each call
+// to the named downstream service, and propagates the span context in the outgoing metadata. The
+// tracer is taken from the call context.
+func UnaryClientInterceptor(serviceName string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		tracer := GetTracer(ctx)
+		ctx, span := tracer.Start(ctx, method, SpanKindClient)
+		defer span.End()
+		span.SetAttribute(AttributeRPCMethod, method)
+		span.SetAttribute(AttributePeerService, serviceName)
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+		tracer.Inject(ctx, MetadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		span.SetAttribute(AttributeRPCStatusCode, int(status.Code(err)))
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a stream client interceptor that records a client span for each
+// call to the named downstream service, as UnaryClientInterceptor does. The span covers the
+// establishment of the stream.
+func StreamClientInterceptor(serviceName string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tracer := GetTracer(ctx)
+		ctx, span := tracer.Start(ctx, method, SpanKindClient)
+		defer span.End()
+		span.SetAttribute(AttributeRPCMethod, method)
+		span.SetAttribute(AttributePeerService, serviceName)
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+		tracer.Inject(ctx, MetadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			span.SetAttribute(AttributeRPCStatusCode, int(status.Code(err)))
+			span.RecordError(err)
+		}
+		return cs, err
+	}
+}
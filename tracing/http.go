@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Attribute keys recorded against HTTP and gRPC spans.
+const (
+	AttributeHTTPMethod     = "http.method"
+	AttributeHTTPURL        = "http.url"
+	AttributeHTTPTarget     = "http.target"
+	AttributeHTTPStatusCode = "http.status_code"
+	AttributeRPCMethod      = "rpc.method"
+	AttributeRPCStatusCode  = "rpc.grpc.status_code"
+	AttributePeerService    = "peer.service"
+)
+
+type roundTripper struct {
+	serviceName string
+	base        http.RoundTripper
+}
+
+// NewRoundTripper returns a round-tripper that records a client span for each request made through
+// the given base round-tripper to the named downstream service, and propagates the span context in
+// the request headers. The tracer is taken from the request context.
+func NewRoundTripper(serviceName string, base http.RoundTripper) http.RoundTripper {
+	return &roundTripper{serviceName: serviceName, base: base}
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	tracer := GetTracer(req.Context())
+	ctx, span := tracer.Start(req.Context(), fmt.Sprintf("HTTP %s", req.Method), SpanKindClient)
+	defer span.End()
+	span.SetAttribute(AttributeHTTPMethod, req.Method)
+	span.SetAttribute(AttributeHTTPURL, req.URL.Redacted())
+	span.SetAttribute(AttributePeerService, t.serviceName)
+
+	// Requests must not be modified by round-trippers, so inject into a copy.
+	req = req.Clone(ctx)
+	tracer.Inject(ctx, HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+	span.SetAttribute(AttributeHTTPStatusCode, resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.RecordError(fmt.Errorf("status %d", resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Carrier holds propagated fields, e.g. the headers of a request.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier adapts HTTP headers to a Carrier.
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// MetadataCarrier adapts gRPC metadata to a Carrier.
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.Join(values, ",")
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Propagator reads and writes span contexts from and to carriers.
+type Propagator interface {
+	// Extract returns the span context held by the carrier, if any.
+	Extract(carrier Carrier) (SpanContext, bool)
+	// Inject writes the span context to the carrier.
+	Inject(sc SpanContext, carrier Carrier)
+}
+
+// Names of the propagators recognised by ParsePropagators.
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorB3           = "b3"
+)
+
+// DefaultPropagators are the propagators used when none are configured.
+var DefaultPropagators = []string{PropagatorTraceContext, PropagatorB3}
+
+// ParsePropagators returns a propagator combining the named propagators, in order.
+// If no names are given then the DefaultPropagators are used.
+func ParsePropagators(names []string) (Propagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+	var composite CompositePropagator
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case PropagatorTraceContext:
+			composite = append(composite, TraceContextPropagator{})
+		case PropagatorB3:
+			composite = append(composite, B3Propagator{})
+		default:
+			return nil, fmt.Errorf("unknown trace propagator %q (expected one of %q)", name, DefaultPropagators)
+		}
+	}
+	return composite, nil
+}
+
+// CompositePropagator extracts with the first of its propagators that finds a span context, and
+// injects with all of its propagators.
+type CompositePropagator []Propagator
+
+func (c CompositePropagator) Extract(carrier Carrier) (SpanContext, bool) {
+	for _, p := range c {
+		if sc, ok := p.Extract(carrier); ok {
+			return sc, true
+		}
+	}
+	return SpanContext{}, false
+}
+
+func (c CompositePropagator) Inject(sc SpanContext, carrier Carrier) {
+	for _, p := range c {
+		p.Inject(sc, carrier)
+	}
+}
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// TraceContextPropagator propagates span contexts in the W3C Trace Context traceparent and
+// tracestate headers (see https://www.w3.org/TR/trace-context/).
+type TraceContextPropagator struct{}
+
+func (TraceContextPropagator) Extract(carrier Carrier) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(carrier.Get(traceparentHeader)), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	// Version 00 has exactly four fields, later versions may append more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	var sc SpanContext
+	var flags [1]byte
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	sc.TraceState = carrier.Get(tracestateHeader)
+	return sc, true
+}
+
+func (TraceContextPropagator) Inject(sc SpanContext, carrier Carrier) {
+	if !sc.IsValid() {
+		return
+	}
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	carrier.Set(traceparentHeader, fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags))
+	if sc.TraceState != "" {
+		carrier.Set(tracestateHeader, sc.TraceState)
+	}
+}
+
+const (
+	b3Header             = "b3"
+	b3TraceIDHeader      = "X-B3-TraceId"
+	b3SpanIDHeader       = "X-B3-SpanId"
+	b3ParentSpanIDHeader = "X-B3-ParentSpanId"
+	b3SampledHeader      = "X-B3-Sampled"
+	b3FlagsHeader        = "X-B3-Flags"
+)
+
+// B3Propagator propagates span contexts in Zipkin B3 headers. It extracts from either the single b3
+// header or the multiple X-B3-* headers, and injects the multiple X-B3-* headers.
+type B3Propagator struct{}
+
+func (B3Propagator) Extract(carrier Carrier) (SpanContext, bool) {
+	if single := carrier.Get(b3Header); single != "" {
+		parts := strings.Split(single, "-")
+		if len(parts) < 2 {
+			return SpanContext{}, false
+		}
+		sampled := ""
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+		return b3SpanContext(parts[0], parts[1], sampled, "")
+	}
+	return b3SpanContext(carrier.Get(b3TraceIDHeader), carrier.Get(b3SpanIDHeader), carrier.Get(b3SampledHeader), carrier.Get(b3FlagsHeader))
+}
+
+func b3SpanContext(traceID, spanID, sampled, flags string) (SpanContext, bool) {
+	var sc SpanContext
+	// 64-bit trace IDs are left-padded to 128 bits.
+	if len(traceID) == 16 {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+	if !decodeHex(sc.TraceID[:], traceID) || !decodeHex(sc.SpanID[:], spanID) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	switch {
+	case flags == "1", sampled == "d":
+		sc.Sampled = true
+	case sampled == "":
+		// The decision is deferred to us, so sample.
+		sc.Sampled = true
+	default:
+		sc.Sampled = sampled == "1" || strings.EqualFold(sampled, "true")
+	}
+	return sc, true
+}
+
+func (B3Propagator) Inject(sc SpanContext, carrier Carrier) {
+	if !sc.IsValid() {
+		return
+	}
+	carrier.Set(b3TraceIDHeader, sc.TraceID.String())
+	carrier.Set(b3SpanIDHeader, sc.SpanID.String())
+	if sc.Sampled {
+		carrier.Set(b3SampledHeader, "1")
+	} else {
+		carrier.Set(b3SampledHeader, "0")
+	}
+}
+
+// decodeHex decodes the lower-case hex string s into dst, which it must exactly fill.
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
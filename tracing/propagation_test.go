@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTraceContextPropagatorExtract(t *testing.T) {
+	tests := []struct {
+		traceparent string
+		ok          bool
+		sampled     bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.traceparent, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("traceparent", tt.traceparent)
+			header.Set("tracestate", "vendor=value")
+			sc, ok := TraceContextPropagator{}.Extract(HeaderCarrier(header))
+			require.Equal(t, tt.ok, ok)
+			if ok {
+				require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+				require.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+				require.Equal(t, tt.sampled, sc.Sampled)
+				require.Equal(t, "vendor=value", sc.TraceState)
+			}
+		})
+	}
+}
+
+func TestTraceContextPropagatorRoundTrip(t *testing.T) {
+	sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true, TraceState: "vendor=value"}
+	md := metadata.MD{}
+	TraceContextPropagator{}.Inject(sc, MetadataCarrier(md))
+	require.Equal(t, []string{"00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-01"}, md.Get("traceparent"))
+
+	extracted, ok := TraceContextPropagator{}.Extract(MetadataCarrier(md))
+	require.True(t, ok)
+	require.Equal(t, sc, extracted)
+}
+
+func TestB3PropagatorExtract(t *testing.T) {
+	t.Run("multi", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-B3-TraceId", "a3ce929d0e0e4736")
+		header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+		header.Set("X-B3-Sampled", "0")
+		sc, ok := B3Propagator{}.Extract(HeaderCarrier(header))
+		require.True(t, ok)
+		require.Equal(t, "0000000000000000a3ce929d0e0e4736", sc.TraceID.String())
+		require.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+		require.False(t, sc.Sampled)
+	})
+	t.Run("single", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("b3", "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1-05e3ac9a4f6e3b90")
+		sc, ok := B3Propagator{}.Extract(HeaderCarrier(header))
+		require.True(t, ok)
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+		require.True(t, sc.Sampled)
+	})
+	t.Run("deferred", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-B3-TraceId", "4bf92f3577b34da6a3ce929d0e0e4736")
+		header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+		sc, ok := B3Propagator{}.Extract(HeaderCarrier(header))
+		require.True(t, ok)
+		require.True(t, sc.Sampled)
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, ok := B3Propagator{}.Extract(HeaderCarrier(http.Header{}))
+		require.False(t, ok)
+	})
+}
+
+func TestParsePropagators(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-B3-TraceId", "4bf92f3577b34da6a3ce929d0e0e4736")
+	header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+
+	// The default propagators fall back to B3 and inject both formats.
+	p, err := ParsePropagators(nil)
+	require.NoError(t, err)
+	sc, ok := p.Extract(HeaderCarrier(header))
+	require.True(t, ok)
+	out := http.Header{}
+	p.Inject(sc, HeaderCarrier(out))
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", out.Get("traceparent"))
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", out.Get("X-B3-TraceId"))
+
+	p, err = ParsePropagators([]string{"tracecontext"})
+	require.NoError(t, err)
+	_, ok = p.Extract(HeaderCarrier(header))
+	require.False(t, ok)
+
+	_, err = ParsePropagators([]string{"jaeger"})
+	require.EqualError(t, err, `unknown trace propagator "jaeger" (expected one of ["tracecontext" "b3"])`)
+}
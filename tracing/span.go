@@ -0,0 +1,188 @@
+// Package tracing provides distributed tracing for sysl-go services: spans for inbound and outbound
+// requests, propagation of span contexts across process boundaries (W3C Trace Context and B3) and
+// pluggable exporters of completed spans.
+//
+// The tracer is implemented by this package and does not use the OpenTelemetry API or SDK. Its
+// propagation formats interoperate with services instrumented with OpenTelemetry, and completed
+// spans can be sent to an OpenTelemetry collector by an Exporter that converts each SpanData.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// IsValid reports whether the ID is non-zero.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid reports whether the ID is non-zero.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext is the part of a span propagated to other processes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	// TraceState is the vendor-specific W3C tracestate, passed through unchanged.
+	TraceState string
+}
+
+// IsValid reports whether the span context has both a trace ID and a span ID.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind describes the relationship of a span to its remote parent or children.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindServer
+	SpanKindClient
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// SpanData is a snapshot of a completed span, as passed to an Exporter.
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	// Err is the error recorded against the span, if any.
+	Err error
+}
+
+// Span is an operation within a trace. A span is exported when it is ended, provided it is sampled.
+type Span struct {
+	exporter Exporter
+
+	m     sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the span context of the span.
+func (s *Span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+// SetAttribute records an attribute against the span.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = map[string]interface{}{}
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError records the error the operation failed with.
+func (s *Span) RecordError(err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.data.Err = err
+}
+
+// End completes the span and exports it. Calls after the first have no effect.
+func (s *Span) End() {
+	s.m.Lock()
+	if s.ended {
+		s.m.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	// Copy the attributes so that the exported data isn't changed by later calls to SetAttribute.
+	if s.data.Attributes != nil {
+		data.Attributes = make(map[string]interface{}, len(s.data.Attributes))
+		for key, value := range s.data.Attributes {
+			data.Attributes[key] = value
+		}
+	}
+	s.m.Unlock()
+
+	if s.exporter != nil && data.SpanContext.Sampled {
+		s.exporter.ExportSpan(data)
+	}
+}
+
+type spanContextKey struct{}
+type remoteSpanContextKey struct{}
+
+// ContextWithSpan returns a copy of the context holding the given span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the span held by the context, or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext returns a copy of the context holding the given span context, received
+// from another process, as the parent of spans subsequently started from the context.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the span held by the context, or else the
+// remote span context held by the context, or else an invalid span context.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return sc
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer starts spans, exporting them to its exporter when they end, and propagates span contexts
+// with its propagator.
+type Tracer struct {
+	exporter   Exporter
+	propagator Propagator
+}
+
+// NewTracer returns a tracer exporting spans to the given exporter (or discarding them if nil) and
+// propagating span contexts with the given propagator (or the DefaultPropagators if nil).
+func NewTracer(exporter Exporter, propagator Propagator) *Tracer {
+	if propagator == nil {
+		propagator, _ = ParsePropagators(nil)
+	}
+	return &Tracer{exporter: exporter, propagator: propagator}
+}
+
+// Start starts a span as the child of the span (or remote span context) held by the context, or as
+// the root of a new trace if there is none. A remote span context with a trace ID but no span ID
+// starts a root span in the given trace. It returns a copy of the context holding the new span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID(), Sampled: true}
+	var parentSpanID SpanID
+	if parent.TraceID.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+		sc.TraceState = parent.TraceState
+		parentSpanID = parent.SpanID
+	} else {
+		sc.TraceID = newTraceID()
+	}
+
+	span := &Span{
+		exporter: t.exporter,
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			SpanContext:  sc,
+			ParentSpanID: parentSpanID,
+			StartTime:    time.Now(),
+		},
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// Extract returns a copy of the context holding the span context found in the carrier, if any, as
+// the remote parent of spans subsequently started from the context.
+func (t *Tracer) Extract(ctx context.Context, carrier Carrier) context.Context {
+	if sc, ok := t.propagator.Extract(carrier); ok {
+		return ContextWithRemoteSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+// Inject writes the span context of the span held by the context to the carrier.
+func (t *Tracer) Inject(ctx context.Context, carrier Carrier) {
+	t.propagator.Inject(SpanContextFromContext(ctx), carrier)
+}
+
+type tracerContextKey struct{}
+
+// defaultTracer is used when there is no tracer in the context. It propagates span contexts but
+// discards spans.
+var defaultTracer = NewTracer(nil, nil)
+
+// PutTracer returns a copy of the context holding the given tracer.
+func PutTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey{}, tracer)
+}
+
+// GetTracer returns the tracer held by the context, or a tracer that discards spans if there is none.
+func GetTracer(ctx context.Context) *Tracer {
+	if tracer, ok := ctx.Value(tracerContextKey{}).(*Tracer); ok {
+		return tracer
+	}
+	return defaultTracer
+}
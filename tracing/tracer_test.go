@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTracerStart(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter, nil)
+
+	ctx, root := tracer.Start(context.Background(), "root", SpanKindServer)
+	_, child := tracer.Start(ctx, "child", SpanKindInternal)
+	child.SetAttribute("key", "value")
+	child.RecordError(errors.New("failed"))
+	child.End()
+	child.End()
+	root.End()
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, root.SpanContext().TraceID, spans[0].SpanContext.TraceID)
+	require.Equal(t, root.SpanContext().SpanID, spans[0].ParentSpanID)
+	require.Equal(t, map[string]interface{}{"key": "value"}, spans[0].Attributes)
+	require.EqualError(t, spans[0].Err, "failed")
+	require.Equal(t, "root", spans[1].Name)
+	require.False(t, spans[1].ParentSpanID.IsValid())
+
+	// Attributes set after the span has ended don't change the exported span.
+	child.SetAttribute("late", "value")
+	require.Equal(t, map[string]interface{}{"key": "value"}, exporter.Spans()[0].Attributes)
+
+	exporter.Reset()
+	require.Empty(t, exporter.Spans())
+}
+
+func TestTracerExtract(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter, nil)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx := tracer.Extract(context.Background(), HeaderCarrier(header))
+	_, span := tracer.Start(ctx, "unsampled", SpanKindServer)
+	span.End()
+
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID.String())
+	require.False(t, span.SpanContext().Sampled)
+	require.Empty(t, exporter.Spans())
+}
+
+func TestGetTracer(t *testing.T) {
+	tracer := NewTracer(nil, nil)
+	require.Equal(t, tracer, GetTracer(PutTracer(context.Background(), tracer)))
+	require.NotNil(t, GetTracer(context.Background()))
+}
+
+func TestRoundTripper(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter, nil)
+	ctx, parent := tracer.Start(PutTracer(context.Background(), tracer), "parent", SpanKindServer)
+	client := &http.Client{Transport: NewRoundTripper("downstream", http.DefaultTransport)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Empty(t, req.Header)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	require.Equal(t, "HTTP GET", span.Name)
+	require.Equal(t, SpanKindClient, span.Kind)
+	require.Equal(t, parent.SpanContext().SpanID, span.ParentSpanID)
+	require.Equal(t, "00-"+span.SpanContext.TraceID.String()+"-"+span.SpanContext.SpanID.String()+"-01", traceparent)
+	require.Equal(t, http.StatusServiceUnavailable, span.Attributes[AttributeHTTPStatusCode])
+	require.Equal(t, "downstream", span.Attributes[AttributePeerService])
+	require.Error(t, span.Err)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter, nil)
+	ctx, parent := tracer.Start(PutTracer(context.Background(), tracer), "parent", SpanKindServer)
+
+	// Pass the outgoing metadata of the client interceptor to the server interceptor.
+	client := UnaryClientInterceptor("downstream")
+	server := UnaryServerInterceptor(tracer)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		ctx = metadata.NewIncomingContext(context.Background(), md)
+		_, err := server(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+		return err
+	}
+	err := client(ctx, "/Test/Method", nil, nil, nil, invoker)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 2)
+	serverSpan, clientSpan := spans[0], spans[1]
+	require.Equal(t, SpanKindServer, serverSpan.Kind)
+	require.Equal(t, SpanKindClient, clientSpan.Kind)
+	require.Equal(t, parent.SpanContext().SpanID, clientSpan.ParentSpanID)
+	require.Equal(t, clientSpan.SpanContext.SpanID, serverSpan.ParentSpanID)
+	require.Equal(t, parent.SpanContext().TraceID, serverSpan.SpanContext.TraceID)
+	require.Equal(t, int(codes.NotFound), serverSpan.Attributes[AttributeRPCStatusCode])
+}